@@ -1,3 +1,5 @@
+// diff_list compara dois arquivos de texto linha por linha e mostra as linhas
+// presentes no primeiro mas ausentes no segundo.
 package main
 
 import (
@@ -8,6 +10,8 @@ import (
 	"os"            // Para interagir com o sistema operacional (arquivos, argumentos)
 	"path/filepath" // Para obter o nome base do programa
 	"strings"       // Para manipulação de strings
+
+	"fsgo/internal/version"
 )
 
 // Variáveis para as flags, definidas fora de main
@@ -17,6 +21,8 @@ var (
 	sufix1Flag = flag.String("sufix1", "", "Sufixo a remover das linhas do <arquivo1> antes da comparação")
 	pre2Flag   = flag.String("pre2", "", "Prefixo a remover das linhas do <arquivo2> antes da comparação")
 	sufix2Flag = flag.String("sufix2", "", "Sufixo a remover das linhas do <arquivo2> antes da comparação")
+
+	showVersion = flag.Bool("version", false, "Exibe a versão e sai")
 )
 
 func main() {
@@ -25,7 +31,7 @@ func main() {
 		output := flag.CommandLine.Output()
 		progName := filepath.Base(os.Args[0])
 
-		fmt.Fprintf(output, "%s: Compara dois arquivos de texto linha por linha e encontra linhas presentes no arquivo1 mas ausentes no arquivo2.\n", progName)
+		fmt.Fprintf(output, "%s %s: Compara dois arquivos de texto linha por linha e encontra linhas presentes no arquivo1 mas ausentes no arquivo2.\n", progName, version.Version)
 		fmt.Fprintf(output, "       Prefixos e sufixos podem ser removidos de cada linha antes da comparação.\n\n")
 		fmt.Fprintf(output, "Uso: %s [opções] <arquivo1> <arquivo2>\n\n", progName)
 		fmt.Fprintf(output, "Argumentos:\n")
@@ -48,6 +54,11 @@ func main() {
 
 	flag.Parse() // Analisa os argumentos da linha de comando
 
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+		return
+	}
+
 	// Verifica se o número correto de argumentos (arquivos) foi fornecido
 	args := flag.Args() // Obtém os argumentos que não são flags
 	if len(args) != 2 {
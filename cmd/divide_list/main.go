@@ -1,3 +1,4 @@
+// divide_list divide um arquivo de texto em um número especificado de partes menores.
 package main
 
 import (
@@ -9,9 +10,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"fsgo/internal/version"
 )
 
-// Nenhuma flag opcional definida por enquanto, mas preparamos para o futuro.
+var showVersion = flag.Bool("version", false, "Exibe a versão e sai")
 
 func main() {
 	// Define a função de Usage personalizada ANTES de flag.Parse()
@@ -19,7 +22,7 @@ func main() {
 		output := flag.CommandLine.Output()
 		progName := filepath.Base(os.Args[0])
 
-		fmt.Fprintf(output, "%s: Divide um arquivo de texto em um número especificado de partes menores.\n\n", progName)
+		fmt.Fprintf(output, "%s %s: Divide um arquivo de texto em um número especificado de partes menores.\n\n", progName, version.Version)
 		fmt.Fprintf(output, "Uso: %s <arquivo_entrada> <num_partes> <diretorio_saida>\n\n", progName)
 		fmt.Fprintf(output, "Argumentos:\n")
 		fmt.Fprintf(output, "  <arquivo_entrada>  O caminho para o arquivo de texto a ser dividido.\n")
@@ -34,9 +37,14 @@ func main() {
 		fmt.Fprintf(output, "  %s grande_lista.txt 10 ./partes\n", progName)
 	}
 
-	// Analisa flags (como -h). Não temos flags customizadas aqui, mas mantém o padrão.
+	// Analisa flags (-h e -version)
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+		return
+	}
+
 	// Verifica se o número correto de argumentos posicionais foi fornecido
 	if flag.NArg() != 3 {
 		fmt.Fprintf(flag.CommandLine.Output(), "Erro: Número incorreto de argumentos fornecidos.\n\n")
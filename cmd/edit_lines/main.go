@@ -0,0 +1,464 @@
+// edit_lines edita cada linha de um arquivo adicionando/removendo prefixos e
+// sufixos ou aplicando scripts de edição.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath" // Importado para obter o nome base do programa
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fsgo/internal/cliflag"
+	"fsgo/internal/prompt"
+	"fsgo/internal/script"
+	"fsgo/internal/textdiff"
+	"fsgo/internal/version"
+)
+
+// Define as flags fora de main
+var (
+	rmpre   = flag.String("rmpre", "", "String a remover do início de cada linha")
+	rmpos   = flag.String("rmpos", "", "String a remover do final de cada linha")
+	addpre  = flag.String("addpre", "", "String a adicionar no início de cada linha")
+	addpos  = flag.String("addpos", "", "String a adicionar no final de cada linha")
+	inplace = flag.Bool("I", false, "Edita o arquivo in-place (sobrescreve o original)")
+	scripts cliflag.StringList
+
+	// Seleção de linhas (endereços no estilo do sed); as demais passam inalteradas
+	linesFlag = flag.String("lines", "", "Edita apenas as linhas nestes intervalos, ex.: '10-200', '5', '100-', '1-3,8-9'")
+	matchFlag = flag.String("match", "", "Edita apenas as linhas que correspondem a esta `regex`")
+	fromFlag  = flag.String("from", "", "Edita apenas a partir da linha que corresponde a esta `regex` (inclusive)")
+	untilFlag = flag.String("until", "", "Edita apenas até a linha que corresponde a esta `regex` (inclusive)")
+
+	// Verificação de -rmpre/-rmpos
+	requireFlag   = flag.Bool("require", false, "Falha (sem alterar nada) se alguma linha selecionada não tiver o prefixo de -rmpre ou o sufixo de -rmpos; as linhas são listadas")
+	reportMissing = flag.Bool("report-missing", false, "Lista as linhas sem o prefixo de -rmpre ou o sufixo de -rmpos, mas continua")
+
+	// Pré-visualização
+	diffMode     = flag.Bool("diff", false, "Exibe um diff unificado (original x resultado) em vez do conteúdo modificado")
+	diffContext  = flag.Int("U", 3, "Exibe `N` linhas de contexto em cada trecho do -diff")
+	confirmWrite = flag.Bool("confirm", false, "Com -I, exibe o diff e pergunta antes de gravar o arquivo")
+
+	showVersion = flag.Bool("version", false, "Exibe a versão e sai")
+)
+
+func init() {
+	flag.Var(&scripts, "e", "Script de edição: operações separadas por '|' (repetível; aplicado depois das flags acima)")
+}
+
+func main() {
+	// Define a função de Usage personalizada ANTES de flag.Parse()
+	flag.Usage = func() {
+		output := flag.CommandLine.Output()
+		progName := filepath.Base(os.Args[0])
+
+		fmt.Fprintf(output, "%s %s: Edita cada linha de um arquivo adicionando/removendo prefixos/sufixos.\n\n", progName, version.Version)
+		fmt.Fprintf(output, "Uso: %s [opções] <arquivo>\n\n", progName)
+		fmt.Fprintf(output, "Argumento:\n")
+		fmt.Fprintf(output, "  <arquivo>  O caminho para o arquivo a ser processado.\n\n")
+		fmt.Fprintf(output, "Opções:\n")
+		// Imprime as descrições padrão de todas as flags definidas
+		flag.PrintDefaults()
+		fmt.Fprintf(output, "\nComportamento Padrão:\n")
+		fmt.Fprintf(output, "  Por padrão, o programa exibe o conteúdo modificado no terminal (stdout).\n")
+		fmt.Fprintf(output, "  O arquivo original não é alterado a menos que a opção -I seja usada.\n")
+		fmt.Fprintf(output, "  Com -diff, apenas as diferenças são exibidas (formato unificado, aceito pelo patch).\n\n")
+		fmt.Fprintf(output, "Exemplos:\n")
+		fmt.Fprintf(output, "  # Adicionar '// ' no início de cada linha de config.txt (mostrar resultado)\n")
+		fmt.Fprintf(output, "  %s -addpre '// ' config.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Remover o sufixo '.tmp' de cada linha e sobrescrever nomes.txt\n")
+		fmt.Fprintf(output, "  %s -I -rmpos '.tmp' nomes.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Remover prefixo 'old_' e adicionar sufixo '.new' em cada linha de list.dat (mostrar resultado)\n")
+		fmt.Fprintf(output, "  %s -rmpre 'old_' -addpos '.new' list.dat\n\n", progName)
+		fmt.Fprintf(output, "  # O mesmo com um script, convertendo para maiúsculas e trocando '/' por '\\'\n")
+		fmt.Fprintf(output, "  %s -e \"trimpre 'old_' | upper | replace '/' '\\\\' | addpos '.new'\" list.dat\n\n", progName)
+		fmt.Fprintf(output, "  # Descomentar as linhas que começam com '#' e comentar as demais\n")
+		fmt.Fprintf(output, "  %s -e \"if /^#/ then trimpre '#' | trim else addpre '# ' end\" config.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Adicionar '> ' apenas nas linhas 10 a 200\n")
+		fmt.Fprintf(output, "  %s -lines 10-200 -addpre '> ' notas.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Comentar apenas o bloco entre os marcadores BEGIN e END (inclusive)\n")
+		fmt.Fprintf(output, "  %s -from '^# BEGIN' -until '^# END' -addpre '// ' config.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Remover o prefixo 'img/' exigindo que todas as linhas o tenham\n")
+		fmt.Fprintf(output, "  %s -require -I -rmpre 'img/' lista.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Ver o que mudaria (diff unificado) e depois gravar pedindo confirmação\n")
+		fmt.Fprintf(output, "  %s -diff -rmpre 'old_' list.dat\n", progName)
+		fmt.Fprintf(output, "  %s -I -confirm -rmpre 'old_' list.dat\n\n", progName)
+		fmt.Fprintf(output, "Seleção de linhas:\n")
+		fmt.Fprintf(output, "  -lines, -match e -from/-until podem ser combinados: a linha precisa satisfazer todos.\n")
+		fmt.Fprintf(output, "  -from/-until funcionam como /início/,/fim/ do sed: o bloco recomeça a cada novo início.\n")
+		fmt.Fprintf(output, "  Linhas fora da seleção são mantidas sem alteração.\n\n")
+		fmt.Fprintf(output, "Operações do script (-e):\n")
+		fmt.Fprintf(output, "  trimpre S, trimpos S   Remove o prefixo/sufixo S (sinônimos: rmpre, rmpos)\n")
+		fmt.Fprintf(output, "  addpre S, addpos S     Adiciona o prefixo/sufixo S\n")
+		fmt.Fprintf(output, "  replace A B            Troca todas as ocorrências de A por B\n")
+		fmt.Fprintf(output, "  sub /regex/ B          Troca as correspondências da regex por B ($1, $2... para grupos)\n")
+		fmt.Fprintf(output, "  upper, lower, trim     Maiúsculas, minúsculas, remove espaços das pontas\n")
+		fmt.Fprintf(output, "  if [not] /regex/ then ... [else ...] [end]   Aplica operações condicionalmente\n")
+	}
+
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+		return
+	}
+
+	// Verifica se o argumento obrigatório <arquivo> foi fornecido
+	if flag.NArg() < 1 {
+		fmt.Fprintf(flag.CommandLine.Output(), "Erro: O argumento <arquivo> é obrigatório.\n\n")
+		flag.Usage() // Mostra a mensagem de uso completa
+		os.Exit(1)   // Sai com código de erro
+	}
+	// Verifica se foram fornecidos argumentos extras inesperados
+	if flag.NArg() > 1 {
+		fmt.Fprintf(flag.CommandLine.Output(), "Erro: Fornecido mais de um argumento de arquivo.\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+	filePath := flag.Arg(0)
+
+	// -confirm só faz sentido com -I e sempre mostra o diff antes da pergunta
+	if *confirmWrite {
+		if !*inplace {
+			log.Fatalf("Erro: A opção -confirm só pode ser usada junto com -I.\n")
+		}
+		*diffMode = true
+	}
+
+	// -require e -report-missing verificam o prefixo/sufixo que -rmpre/-rmpos
+	// removem; sem essas flags não haveria nada a verificar
+	if (*requireFlag || *reportMissing) && *rmpre == "" && *rmpos == "" {
+		log.Fatalf("Erro: As opções -require e -report-missing só podem ser usadas junto com -rmpre ou -rmpos.\n")
+	}
+
+	// As quatro flags são operações embutidas, aplicadas antes dos scripts -e.
+	// Cada flag e cada script vira um passo com sua própria contagem de alterações.
+	var steps []*editStep
+	for _, f := range []struct {
+		name, op, value string
+		suffix          bool
+	}{
+		{"rmpre", "trimpre", *rmpre, false},
+		{"rmpos", "trimpos", *rmpos, true},
+		{"addpre", "addpre", *addpre, false},
+		{"addpos", "addpos", *addpos, false},
+	} {
+		if f.value == "" {
+			continue
+		}
+		op, err := script.Parse(f.op + " " + script.Quote(f.value))
+		if err != nil {
+			log.Fatalf("Erro na flag -%s: %v\n", f.name, err)
+		}
+		step := &editStep{label: fmt.Sprintf("-%s '%s'", f.name, f.value), op: op}
+		// Apenas as remoções podem exigir que o prefixo/sufixo exista
+		if f.name == "rmpre" || f.name == "rmpos" {
+			step.require, step.suffix = f.value, f.suffix
+		}
+		steps = append(steps, step)
+	}
+	for _, src := range scripts {
+		op, err := script.Parse(src)
+		if err != nil {
+			log.Fatalf("Erro no script '%s': %v\n", src, err)
+		}
+		steps = append(steps, &editStep{label: fmt.Sprintf("-e '%s'", src), op: op})
+	}
+
+	selector, err := newLineSelector(*linesFlag, *matchFlag, *fromFlag, *untilFlag)
+	if err != nil {
+		log.Fatalf("Erro: %v\n", err)
+	}
+
+	// Tenta obter informações do arquivo para permissões e verificação de existência
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Fatalf("Erro: Arquivo não encontrado '%s'\n", filePath)
+		}
+		log.Fatalf("Erro ao acessar informações do arquivo '%s': %v\n", filePath, err)
+	}
+	// Verifica se é um diretório (não suportado)
+	if fileInfo.IsDir() {
+		log.Fatalf("Erro: O caminho '%s' é um diretório, não um arquivo.\n", filePath)
+	}
+
+	// Lê o arquivo - log.Fatalf apropriado se falhar após Stat ter sucesso
+	originalData, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Fatalf("Erro ao ler o arquivo '%s': %v\n", filePath, err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(originalData)))
+	var newLines []string
+	linesProcessed := 0
+
+	// Com -diff o conteúdo modificado não é exibido. O diff é montado em memória
+	// (o arquivo inteiro já está) e só vai para o stdout depois do -require.
+	var diff *textdiff.Writer
+	var diffOutput bytes.Buffer
+	if *diffMode {
+		diff = textdiff.NewWriter(&diffOutput, filePath, *diffContext)
+	}
+
+	// Processa linha por linha
+	for scanner.Scan() {
+		line := scanner.Text()
+		linesProcessed++
+		// Aplica as flags e os scripts, na ordem, apenas às linhas selecionadas
+		modifiedLine := line
+		if selector.selects(linesProcessed, line) {
+			for _, step := range steps {
+				modifiedLine = step.apply(linesProcessed, modifiedLine)
+			}
+		}
+		newLines = append(newLines, modifiedLine)
+		if diff != nil {
+			if modifiedLine == line {
+				diff.Keep(line)
+			} else {
+				// Um script pode gerar mais de uma linha (ex.: addpos '\n...')
+				diff.Change(line, strings.Split(modifiedLine, "\n"))
+			}
+		}
+	}
+
+	// Verifica erros do scanner
+	if scannerErr := scanner.Err(); scannerErr != nil {
+		log.Fatalf("Erro durante o processamento das linhas do arquivo '%s': %v\n", filePath, scannerErr)
+	}
+
+	// Junta as linhas modificadas. O Join cuida dos newlines entre as linhas e o
+	// newline final do original é mantido (o -diff compara só as linhas, então
+	// o -I não pode mudar o fim do arquivo sem que a pré-visualização mostre)
+	newContent := strings.Join(newLines, "\n")
+	if len(newLines) > 0 && bytes.HasSuffix(originalData, []byte("\n")) {
+		newContent += "\n"
+	}
+
+	// Resumo e verificação do -require: vão para o stderr quando o resultado
+	// (ou o diff) vai para o stdout
+	report := os.Stderr
+	if *inplace && !*diffMode {
+		report = os.Stdout
+	}
+	missingTotal := 0
+	for _, step := range steps {
+		missingTotal += len(step.missing)
+		if (*requireFlag || *reportMissing) && len(step.missing) > 0 {
+			step.reportMissing(report)
+		}
+	}
+	if *requireFlag && missingTotal > 0 {
+		log.Fatalf("Erro: %d linha(s) não têm o prefixo/sufixo exigido por -require; nenhuma alteração foi feita.\n", missingTotal)
+	}
+	if len(steps) > 0 {
+		fmt.Fprintf(report, "Resumo (%d linhas lidas):\n", linesProcessed)
+		for _, step := range steps {
+			fmt.Fprintf(report, "  %-30s %d linha(s) alterada(s)\n", step.label, step.changed)
+		}
+	}
+
+	hasChanges := true
+	if diff != nil {
+		hasChanges = diff.Finish()
+		os.Stdout.Write(diffOutput.Bytes())
+		if !hasChanges {
+			fmt.Fprintf(report, "Nenhuma diferença: '%s' ficaria igual.\n", filePath)
+		}
+	}
+
+	if *inplace && *confirmWrite {
+		if !hasChanges {
+			return
+		}
+		if !prompt.Confirm(fmt.Sprintf("Gravar as alterações em '%s'?", filePath)) {
+			fmt.Fprintf(report, "Nenhuma alteração foi feita em '%s'.\n", filePath)
+			return
+		}
+	}
+
+	if *inplace {
+		// Edição in-place: sobrescreve o arquivo original
+		fmt.Printf("Modificando arquivo '%s' in-place...\n", filePath)
+
+		// Usa as permissões originais do arquivo
+		perms := fileInfo.Mode().Perm()
+
+		// Escreve o novo conteúdo
+		if err := os.WriteFile(filePath, []byte(newContent), perms); err != nil {
+			log.Fatalf("Erro ao escrever modificações no arquivo '%s': %v\n", filePath, err)
+		}
+		fmt.Printf("Arquivo '%s' modificado com sucesso (%d linhas processadas).\n", filePath, linesProcessed)
+	} else if !*diffMode {
+		// Comportamento padrão: imprime o resultado no stdout
+		// Adiciona um newline final na saída do terminal se o conteúdo não terminar
+		// com um, para melhor formatação no shell.
+		fmt.Print(newContent)
+		if len(newContent) > 0 && !strings.HasSuffix(newContent, "\n") {
+			fmt.Println()
+		}
+	}
+}
+
+// -------------------- Passos de edição --------------------
+
+// maxMissingReported limita quantas linhas sem prefixo/sufixo são listadas
+const maxMissingReported = 20
+
+// editStep é uma operação (flag ou script -e) com a contagem de linhas que ela alterou
+type editStep struct {
+	label   string
+	op      script.Op
+	require string // prefixo/sufixo que a linha deveria ter (-rmpre/-rmpos)
+	suffix  bool   // require é um sufixo
+	changed int    // linhas em que a operação mudou algo
+	missing []int  // números das linhas sem o prefixo/sufixo
+}
+
+func (st *editStep) apply(num int, line string) string {
+	if st.require != "" {
+		has := strings.HasPrefix(line, st.require)
+		if st.suffix {
+			has = strings.HasSuffix(line, st.require)
+		}
+		if !has {
+			st.missing = append(st.missing, num)
+		}
+	}
+	result := st.op(line)
+	if result != line {
+		st.changed++
+	}
+	return result
+}
+
+func (st *editStep) reportMissing(w io.Writer) {
+	what := "prefixo"
+	if st.suffix {
+		what = "sufixo"
+	}
+	fmt.Fprintf(w, "%s: %d linha(s) sem o %s '%s':\n", st.label, len(st.missing), what, st.require)
+	for i, num := range st.missing {
+		if i == maxMissingReported {
+			fmt.Fprintf(w, "  ... e mais %d linha(s)\n", len(st.missing)-maxMissingReported)
+			break
+		}
+		fmt.Fprintf(w, "  linha %d\n", num)
+	}
+}
+
+// -------------------- Seleção de linhas --------------------
+
+// lineRange é um intervalo fechado de números de linha; end 0 = até o fim
+type lineRange struct {
+	start, end int
+}
+
+// lineSelector decide quais linhas são editadas
+type lineSelector struct {
+	ranges  []lineRange
+	match   *regexp.Regexp
+	from    *regexp.Regexp
+	until   *regexp.Regexp
+	inBlock bool // dentro de um bloco -from/-until
+}
+
+func newLineSelector(lines, match, from, until string) (*lineSelector, error) {
+	sel := &lineSelector{}
+	if lines != "" {
+		for _, part := range strings.Split(lines, ",") {
+			r, err := parseLineRange(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("intervalo inválido em -lines '%s': %w", part, err)
+			}
+			sel.ranges = append(sel.ranges, r)
+		}
+	}
+	for _, rx := range []struct {
+		flag string
+		expr string
+		dst  **regexp.Regexp
+	}{{"-match", match, &sel.match}, {"-from", from, &sel.from}, {"-until", until, &sel.until}} {
+		if rx.expr == "" {
+			continue
+		}
+		re, err := regexp.Compile(rx.expr)
+		if err != nil {
+			return nil, fmt.Errorf("regex inválida em %s '%s': %w", rx.flag, rx.expr, err)
+		}
+		*rx.dst = re
+	}
+	// Só -until: o bloco começa na primeira linha
+	sel.inBlock = sel.from == nil && sel.until != nil
+	return sel, nil
+}
+
+// parseLineRange interpreta "N", "N-M", "N-" e "-M"
+func parseLineRange(s string) (lineRange, error) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		endStr = startStr
+	}
+	r := lineRange{start: 1}
+	if startStr != "" {
+		n, err := strconv.Atoi(startStr)
+		if err != nil || n < 1 {
+			return r, fmt.Errorf("número de linha inválido '%s'", startStr)
+		}
+		r.start = n
+	}
+	if endStr != "" {
+		n, err := strconv.Atoi(endStr)
+		if err != nil || n < r.start {
+			return r, fmt.Errorf("fim do intervalo inválido '%s'", endStr)
+		}
+		r.end = n
+	} else if !isRange || startStr == "" {
+		return r, fmt.Errorf("intervalo vazio")
+	}
+	return r, nil
+}
+
+// selects deve ser chamada uma vez por linha, em ordem (mantém o estado dos blocos)
+func (sel *lineSelector) selects(num int, line string) bool {
+	inBlock := true
+	if sel.from != nil || sel.until != nil {
+		if !sel.inBlock && sel.from != nil && sel.from.MatchString(line) {
+			sel.inBlock = true
+			inBlock = true
+			// Como no sed, o fim do bloco só é procurado nas linhas seguintes
+		} else if sel.inBlock {
+			inBlock = true
+			if sel.until != nil && sel.until.MatchString(line) {
+				sel.inBlock = false
+			}
+		} else {
+			inBlock = false
+		}
+	}
+	if !inBlock {
+		return false
+	}
+
+	if len(sel.ranges) > 0 {
+		inRange := false
+		for _, r := range sel.ranges {
+			if num >= r.start && (r.end == 0 || num <= r.end) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+	return sel.match == nil || sel.match.MatchString(line)
+}
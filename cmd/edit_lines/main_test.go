@@ -0,0 +1,172 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fsgo/internal/script"
+)
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    lineRange
+		wantErr bool
+	}{
+		{in: "5", want: lineRange{5, 5}},
+		{in: "10-200", want: lineRange{10, 200}},
+		{in: "100-", want: lineRange{100, 0}},
+		{in: "-3", want: lineRange{1, 3}},
+		{in: "7-7", want: lineRange{7, 7}},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "5-2", wantErr: true},
+		{in: "a-3", wantErr: true},
+		{in: "3-b", wantErr: true},
+		{in: "1-2-3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseLineRange(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLineRange(%q): erro %v, quer erro = %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseLineRange(%q) = %+v, quer %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineSelector(t *testing.T) {
+	lines := []string{"a", "# início", "b", "c", "# fim", "d", "# início", "e"}
+	tests := []struct {
+		name                      string
+		lines, match, from, until string
+		want                      []int // números das linhas selecionadas
+	}{
+		{name: "sem seleção", want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "-lines único", lines: "3", want: []int{3}},
+		{name: "-lines vários", lines: "1-2, 6-", want: []int{1, 2, 6, 7, 8}},
+		{name: "-match", match: "^#", want: []int{2, 5, 7}},
+		{name: "-from/-until inclusive", from: "início", until: "fim", want: []int{2, 3, 4, 5, 7, 8}},
+		{name: "só -from", from: "^d$", want: []int{6, 7, 8}},
+		{name: "só -until", until: "^b$", want: []int{1, 2, 3}},
+		{name: "-until na linha do -from não fecha o bloco", from: "^#", until: "^#", want: []int{2, 3, 4, 5, 7, 8}},
+		{name: "-lines e -match juntos", lines: "1-5", match: "^[a-c]$", want: []int{1, 3, 4}},
+		{name: "bloco e -lines juntos", from: "início", until: "fim", lines: "4-", want: []int{4, 5, 7, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := newLineSelector(tt.lines, tt.match, tt.from, tt.until)
+			if err != nil {
+				t.Fatalf("newLineSelector: %v", err)
+			}
+			var got []int
+			for i, line := range lines {
+				if sel.selects(i+1, line) {
+					got = append(got, i+1)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("linhas selecionadas = %v, quer %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLineSelectorErrors(t *testing.T) {
+	tests := []struct {
+		lines, match, from, until string
+		wantErr                   string
+	}{
+		{lines: "1,x", wantErr: "intervalo inválido em -lines 'x'"},
+		{lines: "3-1", wantErr: "intervalo inválido em -lines '3-1'"},
+		{match: "(", wantErr: "regex inválida em -match"},
+		{from: "[", wantErr: "regex inválida em -from"},
+		{until: "a{2,1}", wantErr: "regex inválida em -until"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantErr, func(t *testing.T) {
+			_, err := newLineSelector(tt.lines, tt.match, tt.from, tt.until)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newLineSelector: erro %v, quer algo com %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// editStep registra as linhas sem o prefixo/sufixo exigido (-require e
+// -report-missing) e conta as que a operação alterou
+func TestEditStepRequire(t *testing.T) {
+	lines := []string{"img/a.png", "b.png", "img/c.jpg", ""}
+	tests := []struct {
+		name        string
+		op          string
+		require     string
+		suffix      bool
+		want        []string
+		wantMissing []int
+		wantChanged int
+	}{
+		{
+			name: "prefixo", op: "trimpre img/", require: "img/",
+			want: []string{"a.png", "b.png", "c.jpg", ""}, wantMissing: []int{2, 4}, wantChanged: 2,
+		},
+		{
+			name: "sufixo", op: "trimpos .png", require: ".png", suffix: true,
+			want: []string{"img/a", "b", "img/c.jpg", ""}, wantMissing: []int{3, 4}, wantChanged: 2,
+		},
+		{
+			name: "sufixo não confunde com prefixo", op: "trimpos img/", require: "img/", suffix: true,
+			want: lines, wantMissing: []int{1, 2, 3, 4},
+		},
+		{
+			name: "sem exigência", op: "addpre x",
+			want: []string{"ximg/a.png", "xb.png", "ximg/c.jpg", "x"}, wantChanged: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := script.Parse(tt.op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st := &editStep{label: tt.op, op: op, require: tt.require, suffix: tt.suffix}
+			var got []string
+			for i, line := range lines {
+				got = append(got, st.apply(i+1, line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultado = %q, quer %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(st.missing, tt.wantMissing) {
+				t.Errorf("linhas sem %q = %v, quer %v", tt.require, st.missing, tt.wantMissing)
+			}
+			if st.changed != tt.wantChanged {
+				t.Errorf("alteradas = %d, quer %d", st.changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestEditStepReportMissing(t *testing.T) {
+	st := &editStep{label: "-rmpos '.bak'", require: ".bak", suffix: true}
+	for i := 1; i <= maxMissingReported+3; i++ {
+		st.missing = append(st.missing, i)
+	}
+	var out strings.Builder
+	st.reportMissing(&out)
+	got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if want := "-rmpos '.bak': 23 linha(s) sem o sufixo '.bak':"; got[0] != want {
+		t.Errorf("cabeçalho = %q, quer %q", got[0], want)
+	}
+	if len(got) != maxMissingReported+2 {
+		t.Errorf("%d linhas no relatório, quer %d", len(got), maxMissingReported+2)
+	}
+	if want := "  ... e mais 3 linha(s)"; got[len(got)-1] != want {
+		t.Errorf("última linha = %q, quer %q", got[len(got)-1], want)
+	}
+}
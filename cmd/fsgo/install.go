@@ -0,0 +1,341 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+)
+
+// installManifest registra o que o -install colocou no prefixo, para que o
+// -uninstall remova exatamente esses arquivos e o -update saiba onde reinstalar
+type installManifest struct {
+    Root        string          `json:"root"` // checkout de onde as ferramentas foram compiladas
+    Prefix      string          `json:"prefix"`
+    Symlink     bool            `json:"symlink"`
+    InstalledAt string          `json:"installed_at"`
+    Files       []installedFile `json:"files"`
+}
+
+type installedFile struct {
+    Tool   string `json:"tool"`
+    Path   string `json:"path"`
+    SHA256 string `json:"sha256,omitempty"` // cópias
+    Target string `json:"target,omitempty"` // links simbólicos (para ./bin/<ferramenta>)
+}
+
+// installManifestPath devolve $XDG_DATA_HOME/fsgo/install.json (~/.local/share por padrão)
+func installManifestPath() (string, error) {
+    dir := os.Getenv("XDG_DATA_HOME")
+    if dir == "" {
+        homeDir, err := os.UserHomeDir()
+        if err != nil {
+            return "", fmt.Errorf("não foi possível obter o diretório home: %w", err)
+        }
+        dir = filepath.Join(homeDir, ".local", "share")
+    }
+    return filepath.Join(dir, "fsgo", "install.json"), nil
+}
+
+// readInstallManifest devolve nil (sem erro) se nada foi instalado
+func readInstallManifest(path string) (*installManifest, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil, nil
+        }
+        return nil, fmt.Errorf("erro ao ler o manifesto '%s': %w", path, err)
+    }
+    var m installManifest
+    if err := json.Unmarshal(data, &m); err != nil {
+        return nil, fmt.Errorf("manifesto '%s' inválido: %w", path, err)
+    }
+    return &m, nil
+}
+
+func writeInstallManifest(path string, m *installManifest) error {
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        return fmt.Errorf("erro ao criar diretório '%s': %w", filepath.Dir(path), err)
+    }
+    data, err := json.MarshalIndent(m, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+        return fmt.Errorf("erro ao gravar o manifesto '%s': %w", path, err)
+    }
+    return nil
+}
+
+// resolvePrefix expande o "~/" (que o shell não expande em -prefix=~/...) e torna o caminho absoluto
+func resolvePrefix(prefix string) (string, error) {
+    homeDir, err := os.UserHomeDir()
+    if err != nil {
+        return "", fmt.Errorf("não foi possível obter o diretório home: %w", err)
+    }
+    switch {
+    case prefix == "":
+        return filepath.Join(homeDir, ".local", "bin"), nil
+    case prefix == "~":
+        return homeDir, nil
+    case strings.HasPrefix(prefix, "~/"):
+        prefix = filepath.Join(homeDir, prefix[2:])
+    }
+    return filepath.Abs(prefix)
+}
+
+// -------------------- -install / -update / -uninstall --------------------
+
+func runInstall() error {
+    prefixDir, err := resolvePrefix(*installPrefix)
+    if err != nil {
+        return err
+    }
+    manifestPath, err := installManifestPath()
+    if err != nil {
+        return err
+    }
+    old, err := readInstallManifest(manifestPath)
+    if err != nil {
+        return err
+    }
+    if old != nil && old.Prefix != prefixDir {
+        return fmt.Errorf("as ferramentas já estão instaladas em '%s'; rode -uninstall antes de instalar em '%s'", old.Prefix, prefixDir)
+    }
+    return installTools(prefixDir, *installSymlink, old, manifestPath)
+}
+
+// runUpdate recompila a partir do checkout (só o que mudou, pelos carimbos do
+// -buildAll) e reinstala no prefixo do manifesto apenas os executáveis diferentes
+func runUpdate() error {
+    manifestPath, err := installManifestPath()
+    if err != nil {
+        return err
+    }
+    old, err := readInstallManifest(manifestPath)
+    if err != nil {
+        return err
+    }
+    if old == nil {
+        return fmt.Errorf("nada instalado (manifesto '%s' não existe); use -install", manifestPath)
+    }
+    if old.Root != projectRoot {
+        log.Printf("Aviso: a instalação veio de '%s'; atualizando a partir de '%s'.", old.Root, projectRoot)
+    }
+    return installTools(old.Prefix, old.Symlink, old, manifestPath)
+}
+
+func installTools(prefixDir string, symlink bool, old *installManifest, manifestPath string) error {
+    results, err := buildTools([]buildTarget{{}})
+    if err != nil {
+        return fmt.Errorf("instalação cancelada: %w", err)
+    }
+    if len(results) == 0 {
+        return fmt.Errorf("nenhuma ferramenta para instalar em '%s'", filepath.Join(projectRoot, cmdDir))
+    }
+    if err := os.MkdirAll(prefixDir, 0755); err != nil {
+        return fmt.Errorf("erro ao criar diretório '%s': %w", prefixDir, err)
+    }
+
+    previous := map[string]installedFile{}
+    if old != nil {
+        for _, f := range old.Files {
+            previous[f.Path] = f
+        }
+    }
+
+    manifest := &installManifest{
+        Root:        projectRoot,
+        Prefix:      prefixDir,
+        Symlink:     symlink,
+        InstalledAt: time.Now().Format(time.RFC3339),
+    }
+    // O manifesto é gravado mesmo se algo falhar no meio, para o -uninstall achar o que já foi copiado
+    defer func() {
+        if err := writeInstallManifest(manifestPath, manifest); err != nil {
+            log.Printf("Aviso: %v", err)
+        }
+    }()
+
+    changed, unchanged := 0, 0
+    target := buildTarget{}
+    for _, res := range results {
+        src := filepath.Join(projectRoot, binDir, target.exeName(res.name))
+        dst := filepath.Join(prefixDir, target.exeName(res.name))
+        prev, ours := previous[dst]
+        delete(previous, dst)
+
+        entry, didChange, err := installFile(res.name, src, dst, symlink, ours)
+        if err != nil {
+            // O que já estava instalado e não foi tocado continua no manifesto
+            if ours {
+                manifest.Files = append(manifest.Files, prev)
+            }
+            for _, f := range previous {
+                manifest.Files = append(manifest.Files, f)
+            }
+            return err
+        }
+        manifest.Files = append(manifest.Files, entry)
+        if didChange {
+            log.Printf("Instalado: %s", dst)
+            changed++
+        } else {
+            unchanged++
+        }
+    }
+
+    // Ferramentas que não existem mais no checkout saem também do prefixo
+    var gone []string
+    for path := range previous {
+        gone = append(gone, path)
+    }
+    sort.Strings(gone)
+    for _, path := range gone {
+        if removed, err := removeInstalled(previous[path]); err != nil {
+            log.Printf("Aviso: %v", err)
+        } else if removed {
+            log.Printf("Removido (não existe mais em ./%s): %s", cmdDir, path)
+        }
+    }
+
+    if changed == 0 && len(gone) == 0 {
+        log.Printf("Nada mudou: as %d ferramenta(s) em '%s' já estão atualizadas.", unchanged, prefixDir)
+    } else {
+        log.Printf("%d ferramenta(s) instalada(s) ou atualizada(s) e %d sem mudança em '%s'.", changed, unchanged, prefixDir)
+    }
+    if !dirInPath(prefixDir) {
+        log.Printf("Aviso: '%s' não está no PATH.", prefixDir)
+    }
+    return nil
+}
+
+// installFile copia (ou liga) src em dst. Um dst que já existe só é
+// substituído se foi instalado pelo fsgo (ours); nada muda se ele já é igual.
+func installFile(tool, src, dst string, symlink, ours bool) (installedFile, bool, error) {
+    entry := installedFile{Tool: tool, Path: dst}
+    if _, err := os.Lstat(dst); err == nil && !ours {
+        return entry, false, fmt.Errorf("'%s' já existe e não foi instalado pelo fsgo; remova-o ou escolha outro -prefix", dst)
+    }
+
+    if symlink {
+        entry.Target = src
+        if link, err := os.Readlink(dst); err == nil && link == src {
+            return entry, false, nil
+        }
+        if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+            return entry, false, fmt.Errorf("erro ao substituir '%s': %w", dst, err)
+        }
+        if err := os.Symlink(src, dst); err != nil {
+            return entry, false, fmt.Errorf("erro ao criar o link '%s': %w", dst, err)
+        }
+        return entry, true, nil
+    }
+
+    hash, err := fileSHA256(src)
+    if err != nil {
+        return entry, false, fmt.Errorf("erro ao ler '%s': %w", src, err)
+    }
+    entry.SHA256 = hash
+    if info, err := os.Lstat(dst); err == nil && info.Mode().IsRegular() {
+        if have, err := fileSHA256(dst); err == nil && have == hash {
+            return entry, false, nil
+        }
+    }
+
+    // Copia para um temporário ao lado e renomeia: um executável em uso não é sobrescrito no meio
+    tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+    if err != nil {
+        return entry, false, fmt.Errorf("erro ao criar arquivo temporário em '%s': %w", filepath.Dir(dst), err)
+    }
+    defer os.Remove(tmp.Name())
+    err = copyFileTo(tmp, src)
+    if closeErr := tmp.Close(); err == nil {
+        err = closeErr
+    }
+    if err == nil {
+        err = os.Chmod(tmp.Name(), 0755)
+    }
+    if err == nil {
+        err = os.Rename(tmp.Name(), dst)
+    }
+    if err != nil {
+        return entry, false, fmt.Errorf("erro ao instalar '%s': %w", dst, err)
+    }
+    return entry, true, nil
+}
+
+// removeInstalled apaga um arquivo do manifesto, a menos que ele tenha sido
+// trocado depois da instalação (aí ele é mantido e só há um aviso)
+func removeInstalled(f installedFile) (bool, error) {
+    if _, err := os.Lstat(f.Path); os.IsNotExist(err) {
+        return false, nil
+    }
+    same := false
+    if f.Target != "" {
+        link, err := os.Readlink(f.Path)
+        same = err == nil && link == f.Target
+    } else {
+        hash, err := fileSHA256(f.Path)
+        same = err == nil && hash == f.SHA256
+    }
+    if !same {
+        return false, fmt.Errorf("'%s' mudou desde a instalação e foi mantido; remova-o manualmente se quiser", f.Path)
+    }
+    if err := os.Remove(f.Path); err != nil {
+        return false, fmt.Errorf("erro ao remover '%s': %w", f.Path, err)
+    }
+    return true, nil
+}
+
+func runUninstall() error {
+    manifestPath, err := installManifestPath()
+    if err != nil {
+        return err
+    }
+    m, err := readInstallManifest(manifestPath)
+    if err != nil {
+        return err
+    }
+    if m == nil {
+        log.Printf("Nada instalado (manifesto '%s' não existe). Nenhuma alteração feita.", manifestPath)
+        return nil
+    }
+
+    removed, kept := 0, 0
+    for _, f := range m.Files {
+        ok, err := removeInstalled(f)
+        switch {
+        case err != nil:
+            log.Printf("Aviso: %v", err)
+            kept++
+        case ok:
+            log.Printf("Removido: %s", f.Path)
+            removed++
+        }
+    }
+    if err := os.Remove(manifestPath); err != nil {
+        return fmt.Errorf("erro ao remover o manifesto '%s': %w", manifestPath, err)
+    }
+    os.Remove(filepath.Dir(manifestPath)) // só sai se estiver vazio; o erro é ignorado
+
+    log.Printf("%d arquivo(s) removido(s) de '%s'.", removed, m.Prefix)
+    if kept > 0 {
+        return fmt.Errorf("%d arquivo(s) alterado(s) depois da instalação foram mantidos", kept)
+    }
+    return nil
+}
+
+// dirInPath diz se dir está no PATH deste processo
+func dirInPath(dir string) bool {
+    for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+        if sameDir(entry, dir) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "runtime"
+    "testing"
+)
+
+func TestInstallManifestPath(t *testing.T) {
+    home := t.TempDir()
+    tests := []struct {
+        name    string
+        xdgData string
+        want    string
+    }{
+        {"XDG_DATA_HOME definido", "/dados", filepath.Join("/dados", "fsgo", "install.json")},
+        {"padrão em ~/.local/share", "", filepath.Join(home, ".local", "share", "fsgo", "install.json")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("HOME", home)
+            t.Setenv("USERPROFILE", home)
+            t.Setenv("XDG_DATA_HOME", tt.xdgData)
+            got, err := installManifestPath()
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Errorf("installManifestPath() = %q, quer %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestInstallManifestRoundTrip(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "fsgo", "install.json")
+
+    // Sem manifesto: nada instalado, sem erro
+    m, err := readInstallManifest(path)
+    if err != nil || m != nil {
+        t.Fatalf("readInstallManifest sem arquivo = %v, %v; quer nil, nil", m, err)
+    }
+
+    want := &installManifest{
+        Root:        "/src/fsgo",
+        Prefix:      "/home/u/.local/bin",
+        InstalledAt: "2026-10-18T12:00:00Z",
+        Files: []installedFile{
+            {Tool: "pop_lines", Path: "/home/u/.local/bin/pop_lines", SHA256: "abc"},
+            {Tool: "edit_lines", Path: "/home/u/.local/bin/edit_lines", Target: "/src/fsgo/bin/edit_lines"},
+        },
+    }
+    if err := writeInstallManifest(path, want); err != nil {
+        t.Fatal(err)
+    }
+    got, err := readInstallManifest(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("manifesto relido = %+v, quer %+v", got, want)
+    }
+
+    if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := readInstallManifest(path); err == nil {
+        t.Error("readInstallManifest: erro esperado para um manifesto inválido")
+    }
+}
+
+func TestResolvePrefix(t *testing.T) {
+    home := t.TempDir()
+    t.Setenv("HOME", home)
+    t.Setenv("USERPROFILE", home)
+    cwd, err := os.Getwd()
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        prefix string
+        want   string
+    }{
+        {"", filepath.Join(home, ".local", "bin")},
+        {"~", home},
+        {"~/bin", filepath.Join(home, "bin")},
+        {"bin", filepath.Join(cwd, "bin")},
+        {filepath.Join(home, "opt"), filepath.Join(home, "opt")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.prefix, func(t *testing.T) {
+            got, err := resolvePrefix(tt.prefix)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Errorf("resolvePrefix(%q) = %q, quer %q", tt.prefix, got, tt.want)
+            }
+        })
+    }
+}
+
+// installFile só substitui o que o fsgo instalou e devolve a entrada do
+// manifesto; removeInstalled só apaga o que ainda corresponde a essa entrada
+func TestInstallFileAndRemove(t *testing.T) {
+    tests := []struct {
+        name        string
+        symlink     bool
+        existing    string // conteúdo já presente no destino ("" = nenhum)
+        ours        bool   // o destino está no manifesto anterior
+        modify      bool   // o destino é alterado depois da instalação
+        wantChanged bool
+        wantErr     bool
+        wantRemoved bool
+    }{
+        {name: "cópia nova", wantChanged: true, wantRemoved: true},
+        {name: "cópia igual não é regravada", existing: "binário", ours: true, wantRemoved: true},
+        {name: "cópia diferente é atualizada", existing: "antigo", ours: true, wantChanged: true, wantRemoved: true},
+        {name: "arquivo de outro não é substituído", existing: "de outro", wantErr: true},
+        {name: "cópia alterada depois é mantida", modify: true, wantChanged: true},
+        {name: "link novo", symlink: true, wantChanged: true, wantRemoved: true},
+        {name: "link trocado depois é mantido", symlink: true, modify: true, wantChanged: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if tt.symlink && runtime.GOOS == "windows" {
+                t.Skip("links simbólicos exigem privilégios no Windows")
+            }
+            dir := t.TempDir()
+            src := filepath.Join(dir, "bin", "tool")
+            dst := filepath.Join(dir, "prefix", "tool")
+            writeFile(t, dir, "bin/tool", "binário")
+            if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+                t.Fatal(err)
+            }
+            if tt.existing != "" {
+                writeFile(t, dir, "prefix/tool", tt.existing)
+            }
+
+            entry, changed, err := installFile("tool", src, dst, tt.symlink, tt.ours)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("installFile: erro %v, quer erro = %v", err, tt.wantErr)
+            }
+            if tt.wantErr {
+                if got, _ := os.ReadFile(dst); string(got) != tt.existing {
+                    t.Errorf("destino = %q, quer %q (intacto)", got, tt.existing)
+                }
+                return
+            }
+            if changed != tt.wantChanged {
+                t.Errorf("installFile mudou = %v, quer %v", changed, tt.wantChanged)
+            }
+            if got, err := os.ReadFile(dst); err != nil || string(got) != "binário" {
+                t.Errorf("destino = %q (%v), quer %q", got, err, "binário")
+            }
+            if tt.symlink != (entry.Target != "") || tt.symlink == (entry.SHA256 != "") {
+                t.Errorf("entrada do manifesto = %+v", entry)
+            }
+
+            if tt.modify {
+                os.Remove(dst)
+                writeFile(t, dir, "prefix/tool", "trocado pelo usuário")
+            }
+            removed, err := removeInstalled(entry)
+            if removed != tt.wantRemoved || (err != nil) == tt.wantRemoved {
+                t.Errorf("removeInstalled = %v, %v; quer removido = %v", removed, err, tt.wantRemoved)
+            }
+            if _, err := os.Lstat(dst); os.IsNotExist(err) != tt.wantRemoved {
+                t.Errorf("destino existe = %v depois do removeInstalled", !os.IsNotExist(err))
+            }
+        })
+    }
+}
+
+func TestRemoveInstalledMissingFile(t *testing.T) {
+    removed, err := removeInstalled(installedFile{Tool: "tool", Path: filepath.Join(t.TempDir(), "tool"), SHA256: "abc"})
+    if removed || err != nil {
+        t.Errorf("removeInstalled de arquivo já apagado = %v, %v; quer false, nil", removed, err)
+    }
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "go/ast"
+    "go/parser"
+    "go/token"
+    "go/types"
+    "log"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "time"
+    "unicode"
+    "unicode/utf8"
+)
+
+// toolInfo é o que o -list sabe sobre cada ferramenta (também é o formato do -json)
+type toolInfo struct {
+    Name        string     `json:"name"`
+    Description string     `json:"description"`
+    Files       []string   `json:"files"`
+    Functions   []string   `json:"functions"`
+    Flags       []flagInfo `json:"flags"`
+    Binary      binaryInfo `json:"binary"`
+}
+
+type flagInfo struct {
+    Name    string `json:"name"`
+    Type    string `json:"type"`
+    Default string `json:"default"` // como está no código-fonte (ex.: "runtime.NumCPU()")
+    Usage   string `json:"usage"`
+}
+
+type binaryInfo struct {
+    Path    string `json:"path"`
+    Status  string `json:"status"` // atualizado, desatualizado, sem carimbo, não compilado ou desconhecido
+    Version string `json:"version,omitempty"`
+}
+
+func runListFunctions() error {
+    rootDir := projectRoot
+    commands, err := findCommands(rootDir)
+    if err != nil {
+        return err
+    }
+
+    goVersion := ""
+    goEnv := exec.Command("go", "env", "GOVERSION")
+    goEnv.Dir = rootDir
+    if out, err := goEnv.Output(); err == nil {
+        goVersion = strings.TrimSpace(string(out))
+    }
+
+    var tools []toolInfo
+    for _, name := range commands {
+        if name == organizerName {
+            continue
+        }
+        info, err := inspectTool(rootDir, name)
+        if err != nil {
+            return err
+        }
+        info.Binary = binaryStatus(rootDir, name, goVersion)
+        tools = append(tools, info)
+    }
+
+    if *listJSON {
+        if tools == nil {
+            tools = []toolInfo{}
+        }
+        enc := json.NewEncoder(os.Stdout)
+        enc.SetIndent("", "  ")
+        return enc.Encode(tools)
+    }
+
+    if len(tools) == 0 {
+        log.Printf("Nenhuma ferramenta encontrada em '%s' (ignorando o organizador).", filepath.Join(rootDir, cmdDir))
+        return nil
+    }
+    for i, t := range tools {
+        if i > 0 {
+            fmt.Println()
+        }
+        printTool(t)
+    }
+    return nil
+}
+
+func printTool(t toolInfo) {
+    fmt.Printf("%s: %s\n", t.Name, t.Description)
+    fmt.Printf("  Binário:  %s (%s", t.Binary.Path, t.Binary.Status)
+    if t.Binary.Version != "" {
+        fmt.Printf(", versão %s", t.Binary.Version)
+    }
+    fmt.Println(")")
+    fmt.Printf("  Arquivos: %s\n", strings.Join(t.Files, ", "))
+
+    if len(t.Flags) > 0 {
+        fmt.Println("  Flags:")
+        tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+        for _, f := range t.Flags {
+            fmt.Fprintf(tw, "    -%s\t%s\t%s\t%s\n", f.Name, f.Type, f.Default, f.Usage)
+        }
+        tw.Flush()
+    }
+    fmt.Printf("  Funções (%d): %s\n", len(t.Functions), strings.Join(t.Functions, ", "))
+}
+
+// inspectTool lê os fontes de cmd/<name> com go/parser (sem compilar) e extrai
+// a descrição (comentário do pacote), as funções declaradas e as flags registradas
+func inspectTool(rootDir, name string) (toolInfo, error) {
+    info := toolInfo{Name: name, Description: "(sem descrição)"}
+    paths, err := filepath.Glob(filepath.Join(rootDir, cmdDir, name, "*.go"))
+    if err != nil {
+        return info, err
+    }
+
+    fset := token.NewFileSet()
+    var files []*ast.File
+    for _, path := range paths {
+        if strings.HasSuffix(path, "_test.go") {
+            continue
+        }
+        file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+        if err != nil {
+            return info, fmt.Errorf("erro ao analisar '%s': %w", path, err)
+        }
+        files = append(files, file)
+        info.Files = append(info.Files, filepath.Base(path))
+        if file.Doc != nil && info.Description == "(sem descrição)" {
+            // "diff_list compara ..." vira "Compara ...": o nome já aparece ao lado
+            desc := strings.TrimPrefix(firstParagraph(file.Doc.Text()), name+" ")
+            if desc != "" && desc != name {
+                first, size := utf8.DecodeRuneInString(desc)
+                info.Description = string(unicode.ToUpper(first)) + desc[size:]
+            }
+        }
+    }
+
+    // Tipos declarados das variáveis, para dizer o tipo das flags registradas com flag.Var
+    varTypes := map[string]string{}
+    for _, file := range files {
+        ast.Inspect(file, func(n ast.Node) bool {
+            if spec, ok := n.(*ast.ValueSpec); ok && spec.Type != nil {
+                for _, ident := range spec.Names {
+                    varTypes[ident.Name] = types.ExprString(spec.Type)
+                }
+            }
+            return true
+        })
+    }
+
+    // Arquivos de plataformas diferentes (owner_unix.go/owner_windows.go) declaram as mesmas funções
+    seen := map[string]bool{}
+    for _, file := range files {
+        for _, decl := range file.Decls {
+            fn, ok := decl.(*ast.FuncDecl)
+            if !ok {
+                continue
+            }
+            fnName := fn.Name.Name
+            if fn.Recv != nil && len(fn.Recv.List) > 0 {
+                fnName = strings.TrimPrefix(types.ExprString(fn.Recv.List[0].Type), "*") + "." + fnName
+            }
+            if !seen[fnName] {
+                seen[fnName] = true
+                info.Functions = append(info.Functions, fnName)
+            }
+        }
+        ast.Inspect(file, func(n ast.Node) bool {
+            if call, ok := n.(*ast.CallExpr); ok {
+                if f, ok := flagFromCall(call, varTypes); ok {
+                    info.Flags = append(info.Flags, f)
+                }
+            }
+            return true
+        })
+    }
+    return info, nil
+}
+
+// flagFromCall reconhece flag.<Tipo>(nome, padrão, uso), flag.<Tipo>Var(&v, nome, padrão, uso)
+// e flag.Var(&v, nome, uso)
+func flagFromCall(call *ast.CallExpr, varTypes map[string]string) (flagInfo, bool) {
+    sel, ok := call.Fun.(*ast.SelectorExpr)
+    if !ok {
+        return flagInfo{}, false
+    }
+    if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "flag" {
+        return flagInfo{}, false
+    }
+
+    kind := sel.Sel.Name
+    args := call.Args
+    switch {
+    case kind == "Var" && len(args) == 3:
+        typ := "valor"
+        if unary, ok := args[0].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+            if ident, ok := unary.X.(*ast.Ident); ok && varTypes[ident.Name] != "" {
+                typ = varTypes[ident.Name]
+            }
+        }
+        return flagInfo{Name: stringArg(args[1]), Type: typ, Usage: stringArg(args[2])}, true
+    case strings.HasSuffix(kind, "Var") && len(args) == 4 && flagTypes[strings.TrimSuffix(kind, "Var")]:
+        args = args[1:]
+        kind = strings.TrimSuffix(kind, "Var")
+    case flagTypes[kind] && len(args) == 3:
+    default:
+        return flagInfo{}, false
+    }
+    return flagInfo{
+        Name:    stringArg(args[0]),
+        Type:    strings.ToLower(kind),
+        Default: types.ExprString(args[1]),
+        Usage:   stringArg(args[2]),
+    }, true
+}
+
+// Funções do pacote flag que registram uma flag de um tipo básico
+var flagTypes = map[string]bool{
+    "Bool": true, "String": true, "Int": true, "Int64": true,
+    "Uint": true, "Uint64": true, "Float64": true, "Duration": true,
+}
+
+// stringArg devolve o valor de um literal de string (também concatenado com +)
+// ou, para qualquer outra expressão, o seu código-fonte
+func stringArg(expr ast.Expr) string {
+    switch e := expr.(type) {
+    case *ast.BasicLit:
+        if e.Kind == token.STRING {
+            if s, err := strconv.Unquote(e.Value); err == nil {
+                return s
+            }
+        }
+    case *ast.BinaryExpr:
+        if e.Op == token.ADD {
+            return stringArg(e.X) + stringArg(e.Y)
+        }
+    }
+    return types.ExprString(expr)
+}
+
+// firstParagraph junta em uma linha o primeiro parágrafo de um comentário
+func firstParagraph(text string) string {
+    para, _, _ := strings.Cut(strings.TrimSpace(text), "\n\n")
+    return strings.Join(strings.Fields(para), " ")
+}
+
+// binaryStatus compara o carimbo de bin/<name> com o hash atual dos fontes e a
+// versão do Go, com a mesma regra do -buildAll (buildStamp.upToDate)
+func binaryStatus(rootDir, name, goVersion string) binaryInfo {
+    target := buildTarget{}
+    binPath := filepath.Join(rootDir, binDir, target.exeName(name))
+    info := binaryInfo{Path: filepath.Join(binDir, target.exeName(name))}
+
+    if _, err := os.Stat(binPath); err != nil {
+        info.Status = "não compilado"
+        return info
+    }
+    info.Version = installedVersion(binPath)
+
+    stamp, err := readBuildStamp(filepath.Join(rootDir, binDir, "."+name+".stamp"))
+    if err != nil {
+        info.Status = "sem carimbo"
+        return info
+    }
+    hash, err := sourceHash(rootDir, "./"+cmdDir+"/"+name, target)
+    if err != nil {
+        log.Printf("Aviso: %v", err)
+        info.Status = "desconhecido"
+        return info
+    }
+    if stamp.upToDate(hash, goVersion) {
+        info.Status = "atualizado"
+    } else {
+        info.Status = "desatualizado"
+    }
+    return info
+}
+
+// installedVersion executa '<binário> -version' e devolve a versão informada
+func installedVersion(binPath string) string {
+    ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+    defer cancel()
+    out, err := exec.CommandContext(ctx, binPath, "-version").Output()
+    if err != nil {
+        return ""
+    }
+    // A saída é "<nome> <versão>"; o nome é descartado
+    line := strings.TrimSpace(string(out))
+    if _, rest, ok := strings.Cut(line, " "); ok {
+        return rest
+    }
+    return line
+}
@@ -0,0 +1,1307 @@
+// fsgo compila, instala no PATH e lista as ferramentas de ./cmd.
+package main
+
+import (
+    "archive/tar"
+    "archive/zip"
+    "compress/gzip"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "os/exec"
+    "path"
+    "path/filepath"
+    "runtime"
+    "runtime/debug"
+    "strings"
+    "sync"
+    "text/tabwriter"
+    "time"
+
+    "fsgo/internal/version"
+)
+
+const (
+    binDir      = "bin"
+    distDir     = "dist" // pacotes (.tar.gz/.zip) gerados com -targets
+    modulePath  = "fsgo" // o go.mod com este módulo marca a raiz do projeto
+    rootMarker  = ".fsgo-root" // marca alternativa da raiz (por exemplo, num fork com outro nome de módulo)
+    versionPkg  = modulePath + "/internal/version" // pacote cujas variáveis recebem a versão via -ldflags -X
+    cmdDir      = "cmd" // cada ferramenta é um pacote main em cmd/<nome>
+    pathComment = "# fsgo" // formato antigo: "# fsgo (<date>)" seguido da linha de PATH
+    blockStart  = "# >>> fsgo >>>"
+    blockEnd    = "# <<< fsgo <<<"
+)
+
+var (
+    // Flags de linha de comando
+    buildAll    = flag.Bool("buildAll", false, "Compila todas as ferramentas de ./cmd e as coloca em ./bin")
+    setupPath   = flag.Bool("setupPath", false, "Adiciona ./bin ao PATH no arquivo de inicialização do shell (se não existir)")
+    unsetupPath = flag.Bool("unsetupPath", false, "Remove o trecho adicionado por -setupPath")
+    listFuncs   = flag.Bool("list", false, "Lista as ferramentas de ./cmd com descrição, flags, funções declaradas e estado do binário em ./bin")
+    listJSON    = flag.Bool("json", false, "Com -list, imprime a lista em JSON (para outras ferramentas)")
+    install     = flag.Bool("install", false, "Compila e instala as ferramentas em -prefix, registrando-as em um manifesto")
+    uninstall   = flag.Bool("uninstall", false, "Remove exatamente o que o -install instalou (conforme o manifesto)")
+    update      = flag.Bool("update", false, "Recompila a partir deste checkout e reinstala apenas as ferramentas que mudaram")
+    doctor      = flag.Bool("doctor", false, "Verifica se as ferramentas estão no PATH: o prefixo do -install ou, sem instalação, ./bin e o arquivo de inicialização")
+    shellName   = flag.String("shell", "", "Shell configurado por -setupPath/-unsetupPath: zsh, bash, fish ou profile (padrão: detectado pelo $SHELL)")
+
+    showVersion = flag.Bool("version", false, "Exibe a versão e sai")
+    rootFlag    = flag.String("root", "", "Raiz do projeto (padrão: $FSGO_ROOT ou o primeiro diretório acima do atual, ou do executável, com o go.mod do módulo fsgo)")
+
+    // Opções do -buildAll
+    buildJobs  = flag.Int("j", runtime.NumCPU(), "Número de ferramentas compiladas em paralelo pelo -buildAll")
+    forceBuild = flag.Bool("force", false, "Com -buildAll, recompila também as ferramentas que já estão atualizadas")
+    targets    = flag.String("targets", "", "Com -buildAll, compila para estas plataformas `os/arch,...` em ./bin/<os>_<arch> e gera pacotes em ./dist")
+    // Opções do -install
+    installPrefix  = flag.String("prefix", "", "Diretório em que o -install coloca as ferramentas (padrão: ~/.local/bin)")
+    installSymlink = flag.Bool("symlink", false, "Com -install, cria links simbólicos para ./bin em vez de copiar os executáveis")
+)
+
+// Guarda o nome do comando (cmd/<nome>) deste organizador
+var organizerName string
+
+// Raiz do projeto (absoluta), resolvida uma vez por findProjectRoot; todos os
+// caminhos (./cmd, ./bin, ./dist) e comandos go/git partem dela
+var projectRoot string
+
+func main() {
+    log.SetFlags(0)
+    determineOrganizerName()
+
+    flag.Usage = usage
+    flag.Parse()
+
+    if *showVersion {
+        fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+        return
+    }
+
+    // Nenhuma flag válida? Mostra usage e sai.
+    if !*buildAll && !*setupPath && !*unsetupPath && !*listFuncs && !*doctor && !*install && !*uninstall && !*update {
+        if flag.NFlag() > 0 {
+            log.Printf("Erro: Flag(s) desconhecida(s) fornecida(s) ou nenhuma ação válida especificada.")
+        } else {
+            log.Println("Nenhuma ação especificada.")
+        }
+        flag.Usage()
+        os.Exit(1)
+    }
+
+    if *setupPath && *unsetupPath {
+        log.Fatalf("Erro: -setupPath e -unsetupPath não podem ser usadas juntas.")
+    }
+    if btoi(*install)+btoi(*uninstall)+btoi(*update) > 1 {
+        log.Fatalf("Erro: use apenas uma entre -install, -uninstall e -update.")
+    }
+
+    // -unsetupPath e -uninstall funcionam sem o checkout (que pode nem existir mais)
+    if *buildAll || *setupPath || *listFuncs || *doctor || *install || *update {
+        root, err := findProjectRoot()
+        if err != nil {
+            log.Fatalf("Erro: %v", err)
+        }
+        projectRoot = root
+        log.Printf("Raiz do projeto: %s", projectRoot)
+    }
+
+    anyError := false
+
+    if *listFuncs {
+        if err := runListFunctions(); err != nil {
+            log.Printf("ERRO durante -list: %v", err)
+            anyError = true
+        }
+        log.Println("---------")
+    }
+
+    if *buildAll {
+        log.Println("--- Iniciando compilação das ferramentas de ./cmd ---")
+        if err := runBuildAll(); err != nil {
+            log.Printf("ERRO durante -buildAll: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Compilação concluída ---")
+        }
+    }
+
+    if *install {
+        log.Println("--- Instalando as ferramentas ---")
+        if err := runInstall(); err != nil {
+            log.Printf("ERRO durante -install: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Instalação concluída ---")
+        }
+    }
+
+    if *update {
+        log.Println("--- Atualizando a instalação ---")
+        if err := runUpdate(); err != nil {
+            log.Printf("ERRO durante -update: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Atualização concluída ---")
+        }
+    }
+
+    if *uninstall {
+        log.Println("--- Removendo a instalação ---")
+        if err := runUninstall(); err != nil {
+            log.Printf("ERRO durante -uninstall: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Remoção concluída ---")
+        }
+    }
+
+    if *setupPath {
+        log.Println("--- Configurando PATH ---")
+        if err := runSetupPath(); err != nil {
+            log.Printf("ERRO durante -setupPath: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Configuração do PATH concluída ---")
+        }
+    }
+
+    if *unsetupPath {
+        log.Println("--- Removendo a configuração do PATH ---")
+        if err := runUnsetupPath(); err != nil {
+            log.Printf("ERRO durante -unsetupPath: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Remoção concluída ---")
+        }
+    }
+
+    if *doctor {
+        log.Println("--- Verificando a instalação ---")
+        if err := runDoctor(); err != nil {
+            log.Printf("ERRO durante -doctor: %v", err)
+            anyError = true
+        } else {
+            log.Println("--- Tudo certo ---")
+        }
+    }
+
+    if anyError {
+        log.Println("\nAVISO: Uma ou mais operações falharam.")
+        os.Exit(1)
+    }
+}
+
+// -------------------- Helpers gerais --------------------
+
+func btoi(b bool) int {
+    if b {
+        return 1
+    }
+    return 0
+}
+
+func usage() {
+    output := flag.CommandLine.Output()
+    progName := filepath.Base(os.Args[0])
+    if progName == "." || progName == "main" {
+        progName = "manip_organize"
+    }
+
+    fmt.Fprintf(output, "%s %s: Ferramenta para compilar, configurar e listar funções do projeto filesystem‑manip.\n\n", progName, version.Version)
+    fmt.Fprintf(output, "Uso: %s [flags]\n\n", progName)
+    fmt.Fprintf(output, "Sobre -buildAll: cada ferramenta é um pacote main em ./cmd/<nome> (módulo Go na raiz)\n")
+    fmt.Fprintf(output, "e é compilada para ./bin/<nome>. O código compartilhado fica em ./internal.\n")
+    fmt.Fprintf(output, "As compilações rodam em paralelo (-j) e são incrementais: cada executável tem um carimbo\n")
+    fmt.Fprintf(output, "(bin/.<nome>.stamp) com o hash dos fontes e a versão do Go; se nada mudou, ele é mantido.\n")
+    fmt.Fprintf(output, "Com -targets, cada plataforma vai para ./bin/<os>_<arch> e é empacotada em ./dist\n")
+    fmt.Fprintf(output, "(.zip para windows, .tar.gz para as demais), com os hashes em ./dist/SHA256SUMS.\n")
+    fmt.Fprintf(output, "A versão (git describe), o commit e a data são gravados em cada ferramenta (veja <ferramenta> -version).\n")
+    fmt.Fprintf(output, "Um commit que não muda os fontes não recompila nada; use -force para gravar a versão nova mesmo assim.\n\n")
+    fmt.Fprintf(output, "Os caminhos ./cmd, ./bin e ./dist são relativos à raiz do projeto: -root, $FSGO_ROOT ou o primeiro\n")
+    fmt.Fprintf(output, "diretório acima do atual (ou do executável) com '%s' ou o go.mod do módulo '%s'.\n\n", rootMarker, modulePath)
+    fmt.Fprintf(output, "Sobre -setupPath: o PATH é configurado em um bloco delimitado por '%s' e '%s'.\n", blockStart, blockEnd)
+    fmt.Fprintf(output, "Rodar de novo (por exemplo, depois de mover o repositório) reescreve o bloco no lugar.\n\n")
+    fmt.Fprintf(output, "Sobre -install: as ferramentas são compiladas e copiadas (ou ligadas, com -symlink) para -prefix;\n")
+    fmt.Fprintf(output, "o que foi instalado fica em $XDG_DATA_HOME/fsgo/install.json, usado por -uninstall e -update.\n")
+    fmt.Fprintf(output, "Arquivos já existentes no prefixo que não vieram do fsgo nunca são sobrescritos.\n\n")
+    fmt.Fprintf(output, "Flags disponíveis:\n")
+    flag.PrintDefaults()
+    fmt.Fprintf(output, "\nExemplos:\n")
+    fmt.Fprintf(output, "  ./%s -buildAll           # Compila ./cmd/* para ./bin\n", progName)
+    fmt.Fprintf(output, "  ./%s -buildAll -force    # Recompila tudo, ignorando os carimbos\n", progName)
+    fmt.Fprintf(output, "  ./%s -buildAll -targets linux/amd64,linux/arm64,windows/amd64,darwin/arm64\n", progName)
+    fmt.Fprintf(output, "  ./%s -setupPath          # Adiciona ./bin ao PATH do shell atual ($SHELL)\n", progName)
+    fmt.Fprintf(output, "  ./%s -setupPath -shell fish # Usa fish_add_path -g em ~/.config/fish/config.fish\n", progName)
+    fmt.Fprintf(output, "  ./%s -unsetupPath        # Remove o trecho adicionado (de todos os shells)\n", progName)
+    fmt.Fprintf(output, "  ./%s -doctor             # Verifica se as ferramentas (./bin ou o -install) estão de fato no PATH\n", progName)
+    fmt.Fprintf(output, "  ./%s -list               # Lista as ferramentas: descrição, flags, funções e estado do binário\n", progName)
+    fmt.Fprintf(output, "  ./%s -list -json         # O mesmo, em JSON\n", progName)
+    fmt.Fprintf(output, "  ./%s -install            # Instala as ferramentas em ~/.local/bin\n", progName)
+    fmt.Fprintf(output, "  ./%s -install -symlink -prefix /opt/fsgo/bin # Links para ./bin em outro prefixo\n", progName)
+    fmt.Fprintf(output, "  %s -update                # Recompila e reinstala só o que mudou no checkout\n", progName)
+    fmt.Fprintf(output, "  %s -uninstall             # Remove o que o -install instalou\n", progName)
+    fmt.Fprintf(output, "  ./%s -buildAll -setupPath # Compila e configura o PATH\n", progName)
+    fmt.Fprintf(output, "  %s -root ~/src/fsgo -list # Lista as ferramentas de outro checkout, de qualquer diretório\n", progName)
+}
+
+// Determina o nome do comando deste organizador (cmd/<nome>) para ignorá‑lo no -list.
+// O caminho do pacote main vem das informações de build, não do nome do executável.
+func determineOrganizerName() {
+    organizerName = "fsgo"
+    if info, ok := debug.ReadBuildInfo(); ok && strings.HasPrefix(info.Path, modulePath+"/"+cmdDir+"/") {
+        organizerName = path.Base(info.Path)
+    }
+}
+
+// findProjectRoot resolve a raiz do projeto, nesta ordem: -root, $FSGO_ROOT, o
+// primeiro diretório acima do atual com a marca da raiz, o mesmo a partir do
+// executável (para quem roda ./bin/fsgo de outro lugar) e, por fim, o checkout
+// registrado no manifesto do -install
+func findProjectRoot() (string, error) {
+    if *rootFlag != "" {
+        return checkRoot(*rootFlag, "-root")
+    }
+    if dir := os.Getenv("FSGO_ROOT"); dir != "" {
+        return checkRoot(dir, "FSGO_ROOT")
+    }
+
+    var starts []string
+    if wd, err := os.Getwd(); err == nil {
+        starts = append(starts, wd)
+    }
+    if exePath, err := os.Executable(); err == nil {
+        if exePath, err = filepath.EvalSymlinks(exePath); err == nil {
+            starts = append(starts, filepath.Dir(exePath))
+        }
+    }
+    for _, start := range starts {
+        for dir := start; ; dir = filepath.Dir(dir) {
+            if isProjectRoot(dir) {
+                return dir, nil
+            }
+            if filepath.Dir(dir) == dir {
+                break
+            }
+        }
+    }
+    // Uma cópia instalada por -install (longe do checkout) usa o checkout de onde veio
+    if manifestPath, err := installManifestPath(); err == nil {
+        if m, err := readInstallManifest(manifestPath); err == nil && m != nil && isProjectRoot(m.Root) {
+            return m.Root, nil
+        }
+    }
+    return "", fmt.Errorf("raiz do projeto não encontrada (procurado '%s' ou go.mod do módulo '%s' acima de %s); use -root ou FSGO_ROOT",
+        rootMarker, modulePath, strings.Join(starts, " e "))
+}
+
+// isProjectRoot diz se dir tem a marca da raiz ou o go.mod do módulo fsgo
+func isProjectRoot(dir string) bool {
+    if _, err := os.Stat(filepath.Join(dir, rootMarker)); err == nil {
+        return true
+    }
+    data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if err != nil {
+        return false
+    }
+    for _, line := range strings.Split(string(data), "\n") {
+        fields := strings.Fields(line)
+        if len(fields) >= 2 && fields[0] == "module" {
+            return strings.Trim(fields[1], `"`) == modulePath
+        }
+    }
+    return false
+}
+
+// checkRoot valida uma raiz dada explicitamente (source diz de onde ela veio)
+func checkRoot(dir, source string) (string, error) {
+    absDir, err := filepath.Abs(dir)
+    if err != nil {
+        return "", fmt.Errorf("não foi possível resolver caminho absoluto de '%s' (%s): %w", dir, source, err)
+    }
+    if info, err := os.Stat(filepath.Join(absDir, cmdDir)); err != nil || !info.IsDir() {
+        return "", fmt.Errorf("'%s' (%s) não é a raiz do projeto: falta o diretório '%s'", absDir, source, cmdDir)
+    }
+    return absDir, nil
+}
+
+// findCommands devolve, em ordem alfabética, os nomes dos diretórios de
+// rootDir/cmd que contêm arquivos .go (cada um é uma ferramenta)
+func findCommands(rootDir string) ([]string, error) {
+    entries, err := os.ReadDir(filepath.Join(rootDir, cmdDir))
+    if err != nil {
+        return nil, fmt.Errorf("erro ao ler diretório '%s': %w", filepath.Join(rootDir, cmdDir), err)
+    }
+
+    var commands []string
+    for _, entry := range entries {
+        if !entry.IsDir() {
+            continue
+        }
+        sources, err := filepath.Glob(filepath.Join(rootDir, cmdDir, entry.Name(), "*.go"))
+        if err != nil || len(sources) == 0 {
+            continue
+        }
+        commands = append(commands, entry.Name())
+    }
+    return commands, nil // os.ReadDir já devolve em ordem
+}
+
+// -------------------- -buildAll --------------------
+
+// buildStamp é gravado ao lado de cada executável (bin/.<nome>.stamp) depois de
+// uma compilação bem-sucedida; se os fontes e o Go não mudaram, ela é pulada
+type buildStamp struct {
+    SourceHash string `json:"source_hash"`
+    GoVersion  string `json:"go_version"`
+    Version    string `json:"version"` // versão e commit injetados; só informativo
+}
+
+// upToDate é a regra única (do -buildAll, -list e -update) para um executável
+// não precisar ser recompilado: mesmos fontes e mesmo Go. A versão do
+// repositório fica de fora de propósito: um commit que só muda o README não
+// recompila nada, e o -version do executável mostra o commit em que ele foi
+// de fato compilado.
+func (s buildStamp) upToDate(sourceHash, goVersion string) bool {
+    return s.SourceHash == sourceHash && s.GoVersion == goVersion
+}
+
+// buildVersion é o que o -buildAll injeta em fsgo/internal/version
+type buildVersion struct {
+    version, commit, date string
+}
+
+// detectVersion usa o git da raiz do projeto; fora de um repositório a versão fica "dev"
+func detectVersion() buildVersion {
+    v := buildVersion{version: "dev", date: time.Now().UTC().Format(time.RFC3339)}
+    describe := exec.Command("git", "describe", "--tags", "--always", "--dirty")
+    describe.Dir = projectRoot
+    if out, err := describe.Output(); err == nil {
+        v.version = strings.TrimSpace(string(out))
+    }
+    revParse := exec.Command("git", "rev-parse", "--short", "HEAD")
+    revParse.Dir = projectRoot
+    if out, err := revParse.Output(); err == nil {
+        v.commit = strings.TrimSpace(string(out))
+    }
+    return v
+}
+
+// ldflags monta o -ldflags do go build com as três variáveis
+func (v buildVersion) ldflags() string {
+    return fmt.Sprintf("-X %s.Version=%s -X %s.Commit=%s -X %s.Date=%s",
+        versionPkg, v.version, versionPkg, v.commit, versionPkg, v.date)
+}
+
+// buildTarget é uma plataforma de -targets; a plataforma nativa tem os campos
+// vazios e compila direto em ./bin
+type buildTarget struct {
+    goos, goarch string
+}
+
+// dir é o subdiretório de ./bin (e o sufixo do pacote) da plataforma
+func (t buildTarget) dir() string {
+    if t.goos == "" {
+        return ""
+    }
+    return t.goos + "_" + t.goarch
+}
+
+// String devolve "os/arch" (a plataforma atual, para a nativa)
+func (t buildTarget) String() string {
+    if t.goos == "" {
+        return runtime.GOOS + "/" + runtime.GOARCH
+    }
+    return t.goos + "/" + t.goarch
+}
+
+func (t buildTarget) exeName(name string) string {
+    if t.goos == "windows" {
+        return name + ".exe"
+    }
+    return name
+}
+
+// env devolve o ambiente do go build/go list para a plataforma
+func (t buildTarget) env() []string {
+    env := os.Environ()
+    if t.goos != "" {
+        env = append(env, "GOOS="+t.goos, "GOARCH="+t.goarch, "CGO_ENABLED=0")
+    }
+    return env
+}
+
+// parseTargets valida a lista de -targets com as plataformas conhecidas pelo Go
+func parseTargets(list string) ([]buildTarget, error) {
+    out, err := exec.Command("go", "tool", "dist", "list").Output()
+    if err != nil {
+        return nil, fmt.Errorf("erro ao listar as plataformas suportadas pelo Go: %w", err)
+    }
+    known := make(map[string]bool)
+    for _, platform := range strings.Fields(string(out)) {
+        known[platform] = true
+    }
+
+    var result []buildTarget
+    seen := make(map[string]bool)
+    for _, item := range strings.Split(list, ",") {
+        item = strings.TrimSpace(item)
+        if item == "" || seen[item] {
+            continue
+        }
+        if !known[item] {
+            return nil, fmt.Errorf("plataforma desconhecida em -targets: '%s' (veja 'go tool dist list')", item)
+        }
+        seen[item] = true
+        parts := strings.SplitN(item, "/", 2)
+        result = append(result, buildTarget{goos: parts[0], goarch: parts[1]})
+    }
+    if len(result) == 0 {
+        return nil, fmt.Errorf("nenhuma plataforma informada em -targets")
+    }
+    return result, nil
+}
+
+// buildResult é uma linha da tabela de resumo do -buildAll
+type buildResult struct {
+    name     string
+    target   buildTarget
+    status   string // "compilado", "atualizado" ou "FALHOU"
+    duration time.Duration
+    output   string // saída do go build quando falha
+    err      error
+}
+
+func runBuildAll() error {
+    // Sem -targets, apenas a plataforma nativa, direto em ./bin
+    buildTargets := []buildTarget{{}}
+    if *targets != "" {
+        var err error
+        if buildTargets, err = parseTargets(*targets); err != nil {
+            return err
+        }
+    }
+
+    results, err := buildTools(buildTargets)
+    if results == nil {
+        return err
+    }
+    if *targets != "" {
+        if err := packageTargets(buildTargets, results, filepath.Join(projectRoot, binDir)); err != nil {
+            return err
+        }
+    }
+    return err
+}
+
+// buildTools compila todas as ferramentas para cada plataforma e mostra o resumo.
+// Os resultados voltam mesmo quando alguma compilação falha (o erro diz quantas);
+// sem resultados, o erro impediu a compilação de começar.
+func buildTools(buildTargets []buildTarget) ([]buildResult, error) {
+    absBinDir := filepath.Join(projectRoot, binDir)
+
+    log.Printf("Garantindo que o diretório de saída '%s' existe...", absBinDir)
+    if err := os.MkdirAll(absBinDir, 0755); err != nil {
+        return nil, fmt.Errorf("erro ao criar diretório '%s': %w", absBinDir, err)
+    }
+
+    commands, err := findCommands(projectRoot)
+    if err != nil {
+        return nil, err
+    }
+    if len(commands) == 0 {
+        log.Printf("Nenhuma ferramenta encontrada em '%s'.", filepath.Join(projectRoot, cmdDir))
+        return nil, nil
+    }
+
+    // A versão do Go faz parte do carimbo: trocar de Go recompila tudo
+    goEnv := exec.Command("go", "env", "GOVERSION")
+    goEnv.Dir = projectRoot
+    goVersion, err := goEnv.Output()
+    if err != nil {
+        return nil, fmt.Errorf("erro ao obter a versão do Go: %w", err)
+    }
+
+    ver := detectVersion()
+    log.Printf("Versão: %s (commit %s)", ver.version, ver.commit)
+
+    workers := *buildJobs
+    if workers < 1 {
+        workers = 1
+    }
+    log.Printf("Compilando %d ferramenta(s) de '%s' para %d plataforma(s) (%d em paralelo)...",
+        len(commands), filepath.Join(projectRoot, cmdDir), len(buildTargets), workers)
+
+    // Uma tarefa por ferramenta e plataforma, na ordem da tabela de resumo
+    var results []buildResult
+    for _, target := range buildTargets {
+        for _, name := range commands {
+            results = append(results, buildResult{name: name, target: target})
+        }
+    }
+    jobs := make(chan int)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                results[i] = buildCommand(results[i].name, results[i].target, absBinDir, strings.TrimSpace(string(goVersion)), ver)
+            }
+        }()
+    }
+    for i := range results {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+
+    return results, printBuildSummary(results, absBinDir)
+}
+
+// buildCommand compila cmd/<name> para bin/[<os>_<arch>/]<name>, a menos que o
+// carimbo mostre que o executável já corresponde aos fontes atuais
+func buildCommand(name string, target buildTarget, absBinDir, goVersion string, ver buildVersion) buildResult {
+    start := time.Now()
+    res := buildResult{name: name, target: target}
+    pkgPath := "./" + cmdDir + "/" + name
+    outDir := filepath.Join(absBinDir, target.dir())
+    outputPath := filepath.Join(outDir, target.exeName(name))
+    stampPath := filepath.Join(outDir, "."+name+".stamp")
+
+    if err := os.MkdirAll(outDir, 0755); err != nil {
+        res.status, res.err = "FALHOU", err
+        return res
+    }
+    hash, err := sourceHash(projectRoot, pkgPath, target)
+    if err != nil {
+        res.status, res.err = "FALHOU", err
+        res.duration = time.Since(start)
+        return res
+    }
+    want := buildStamp{SourceHash: hash, GoVersion: goVersion, Version: ver.version + " " + ver.commit}
+
+    if !*forceBuild {
+        if _, err := os.Stat(outputPath); err == nil {
+            if have, err := readBuildStamp(stampPath); err == nil && have.upToDate(want.SourceHash, want.GoVersion) {
+                res.status = "atualizado"
+                res.duration = time.Since(start)
+                return res
+            }
+        }
+    }
+
+    // Carimbo antigo é removido antes: se a compilação falhar no meio, a próxima não é pulada
+    os.Remove(stampPath)
+    cmd := exec.Command("go", "build", "-ldflags", ver.ldflags(), "-o", outputPath, pkgPath)
+    cmd.Env = target.env()
+    cmd.Dir = projectRoot
+    out, err := cmd.CombinedOutput()
+    res.duration = time.Since(start)
+    if err != nil {
+        res.status, res.err, res.output = "FALHOU", err, string(out)
+        return res
+    }
+    if err := writeBuildStamp(stampPath, want); err != nil {
+        log.Printf("Aviso: não foi possível gravar o carimbo de '%s': %v", name, err)
+    }
+    res.status = "compilado"
+    return res
+}
+
+// sourceHash calcula o SHA-256 dos arquivos .go de todos os pacotes do módulo
+// dos quais pkgPath depende (o próprio comando e os de ./internal), além do go.mod/go.sum.
+// Os arquivos dependem da plataforma (build tags), por isso o go list roda com o seu GOOS/GOARCH.
+// pkgPath e go.mod/go.sum são relativos a rootDir.
+func sourceHash(rootDir, pkgPath string, target buildTarget) (string, error) {
+    list := exec.Command("go", "list", "-deps", "-f",
+        `{{if not .Standard}}{{.Dir}}{{range .GoFiles}}{{"\t"}}{{.}}{{end}}{{end}}`, pkgPath)
+    list.Env = target.env()
+    list.Dir = rootDir
+    out, err := list.Output()
+    if err != nil {
+        if exitErr, ok := err.(*exec.ExitError); ok {
+            return "", fmt.Errorf("erro ao listar os pacotes de '%s': %s", pkgPath, strings.TrimSpace(string(exitErr.Stderr)))
+        }
+        return "", fmt.Errorf("erro ao listar os pacotes de '%s': %w", pkgPath, err)
+    }
+
+    files := []string{"go.mod", "go.sum"}
+    for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+        fields := strings.Split(line, "\t")
+        if len(fields) < 2 {
+            continue
+        }
+        for _, name := range fields[1:] {
+            files = append(files, filepath.Join(fields[0], name))
+        }
+    }
+
+    h := sha256.New()
+    for _, path := range files {
+        openPath := path
+        if !filepath.IsAbs(path) {
+            openPath = filepath.Join(rootDir, path)
+        }
+        file, err := os.Open(openPath)
+        if err != nil {
+            if os.IsNotExist(err) && (path == "go.mod" || path == "go.sum") {
+                continue
+            }
+            return "", fmt.Errorf("erro ao ler '%s': %w", path, err)
+        }
+        // O nome entra no hash para que mover código entre arquivos também conte
+        fmt.Fprintf(h, "%s\x00", path)
+        _, err = io.Copy(h, file)
+        file.Close()
+        if err != nil {
+            return "", fmt.Errorf("erro ao ler '%s': %w", path, err)
+        }
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readBuildStamp(path string) (buildStamp, error) {
+    var stamp buildStamp
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return stamp, err
+    }
+    err = json.Unmarshal(data, &stamp)
+    return stamp, err
+}
+
+func writeBuildStamp(path string, stamp buildStamp) error {
+    data, err := json.MarshalIndent(stamp, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// printBuildSummary mostra a tabela de resultados e a saída das compilações que falharam
+func printBuildSummary(results []buildResult, absBinDir string) error {
+    built, skipped, failed := 0, 0, 0
+    tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(tw, "FERRAMENTA\tPLATAFORMA\tRESULTADO\tDURAÇÃO")
+    for _, res := range results {
+        fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.name, res.target, res.status, res.duration.Round(time.Millisecond))
+        switch res.status {
+        case "compilado":
+            built++
+        case "atualizado":
+            skipped++
+        default:
+            failed++
+        }
+    }
+    tw.Flush()
+
+    for _, res := range results {
+        if res.err == nil {
+            continue
+        }
+        log.Printf("\nERRO ao compilar '%s' (%s): %v", res.name, res.target, res.err)
+        if res.output != "" {
+            log.Print(strings.TrimRight(res.output, "\n"))
+        }
+    }
+
+    log.Printf("%d compilada(s), %d já atualizada(s), %d com erro. Executáveis em '%s'.", built, skipped, failed, absBinDir)
+    if failed > 0 {
+        return fmt.Errorf("%d ferramenta(s) não puderam ser compiladas", failed)
+    }
+    return nil
+}
+
+// -------------------- Pacotes (-targets) --------------------
+
+// packageTargets gera dist/fsgo_<os>_<arch>.tar.gz (ou .zip para windows) com
+// os executáveis de cada plataforma e grava os hashes em dist/SHA256SUMS, no
+// formato do sha256sum (verificável com 'sha256sum -c SHA256SUMS'). Plataformas
+// com alguma compilação falha não são empacotadas.
+func packageTargets(buildTargets []buildTarget, results []buildResult, absBinDir string) error {
+    absDistDir := filepath.Join(projectRoot, distDir)
+    if err := os.MkdirAll(absDistDir, 0755); err != nil {
+        return fmt.Errorf("erro ao criar diretório '%s': %w", absDistDir, err)
+    }
+
+    var sums strings.Builder
+    packaged := 0
+    for _, target := range buildTargets {
+        var files []string
+        complete := true
+        for _, res := range results {
+            if res.target != target {
+                continue
+            }
+            if res.err != nil {
+                complete = false
+                break
+            }
+            files = append(files, filepath.Join(absBinDir, target.dir(), target.exeName(res.name)))
+        }
+        baseName := "fsgo_" + target.dir()
+        archivePath := filepath.Join(absDistDir, baseName+".tar.gz")
+        if target.goos == "windows" {
+            archivePath = filepath.Join(absDistDir, baseName+".zip")
+        }
+        if !complete {
+            // Um pacote antigo desta plataforma não pode parecer atual
+            os.Remove(archivePath)
+            log.Printf("Aviso: %s não foi empacotada porque houve erros de compilação.", target)
+            continue
+        }
+
+        var err error
+        if target.goos == "windows" {
+            err = writeZip(archivePath, baseName, files)
+        } else {
+            err = writeTarGz(archivePath, baseName, files)
+        }
+        if err != nil {
+            os.Remove(archivePath)
+            return fmt.Errorf("erro ao gerar o pacote '%s': %w", archivePath, err)
+        }
+        sum, err := fileSHA256(archivePath)
+        if err != nil {
+            return fmt.Errorf("erro ao calcular o hash de '%s': %w", archivePath, err)
+        }
+        fmt.Fprintf(&sums, "%s  %s\n", sum, filepath.Base(archivePath))
+        log.Printf("✔ Pacote gerado: %s", archivePath)
+        packaged++
+    }
+
+    if packaged == 0 {
+        return nil
+    }
+    sumsPath := filepath.Join(absDistDir, "SHA256SUMS")
+    if err := os.WriteFile(sumsPath, []byte(sums.String()), 0644); err != nil {
+        return fmt.Errorf("erro ao gravar '%s': %w", sumsPath, err)
+    }
+    log.Printf("Hashes SHA-256 gravados em '%s'.", sumsPath)
+    return nil
+}
+
+// writeTarGz empacota files dentro do diretório dirName do arquivo
+func writeTarGz(archivePath, dirName string, files []string) error {
+    out, err := os.Create(archivePath)
+    if err != nil {
+        return err
+    }
+    defer out.Close()
+    gz := gzip.NewWriter(out)
+    tw := tar.NewWriter(gz)
+
+    for _, path := range files {
+        info, err := os.Stat(path)
+        if err != nil {
+            return err
+        }
+        header, err := tar.FileInfoHeader(info, "")
+        if err != nil {
+            return err
+        }
+        header.Name = dirName + "/" + filepath.Base(path)
+        if err := tw.WriteHeader(header); err != nil {
+            return err
+        }
+        if err := copyFileTo(tw, path); err != nil {
+            return err
+        }
+    }
+
+    if err := tw.Close(); err != nil {
+        return err
+    }
+    if err := gz.Close(); err != nil {
+        return err
+    }
+    return out.Close()
+}
+
+// writeZip empacota files dentro do diretório dirName do arquivo
+func writeZip(archivePath, dirName string, files []string) error {
+    out, err := os.Create(archivePath)
+    if err != nil {
+        return err
+    }
+    defer out.Close()
+    zw := zip.NewWriter(out)
+
+    for _, path := range files {
+        info, err := os.Stat(path)
+        if err != nil {
+            return err
+        }
+        header, err := zip.FileInfoHeader(info)
+        if err != nil {
+            return err
+        }
+        header.Name = dirName + "/" + filepath.Base(path)
+        header.Method = zip.Deflate
+        w, err := zw.CreateHeader(header)
+        if err != nil {
+            return err
+        }
+        if err := copyFileTo(w, path); err != nil {
+            return err
+        }
+    }
+
+    if err := zw.Close(); err != nil {
+        return err
+    }
+    return out.Close()
+}
+
+func copyFileTo(w io.Writer, path string) error {
+    file, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    _, err = io.Copy(w, file)
+    return err
+}
+
+func fileSHA256(path string) (string, error) {
+    h := sha256.New()
+    if err := copyFileTo(h, path); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// -------------------- -setupPath --------------------
+
+// shellConfig descreve onde e como cada shell recebe o ./bin no PATH
+type shellConfig struct {
+    name   string // zsh, bash, fish ou profile
+    rcPath string // arquivo de inicialização lido pelo shell
+}
+
+var knownShells = []string{"zsh", "bash", "fish", "profile"}
+
+// pathLine devolve a linha que coloca binPath no início do PATH, na sintaxe do shell.
+// No fish, o -g é essencial: sem ele o fish_add_path grava o caminho na variável
+// universal fish_user_paths, que fica fora do config.fish e sobreviveria ao
+// -unsetupPath (ou a uma mudança do repositório de lugar).
+func (sc shellConfig) pathLine(binPath string) string {
+    if sc.name == "fish" {
+        return fmt.Sprintf("fish_add_path -g \"%s\"", binPath)
+    }
+    return fmt.Sprintf("export PATH=\"%s:$PATH\"", binPath)
+}
+
+// detectShell escolhe o shell pelo -shell ou, sem ele, pelo $SHELL; shells
+// desconhecidos (sh, dash, ksh...) usam o ~/.profile, lido por shells de login
+func detectShell() (string, error) {
+    if *shellName != "" {
+        for _, name := range knownShells {
+            if *shellName == name {
+                return name, nil
+            }
+        }
+        return "", fmt.Errorf("shell inválido para -shell: '%s' (use zsh, bash, fish ou profile)", *shellName)
+    }
+    switch name := filepath.Base(os.Getenv("SHELL")); name {
+    case "zsh", "bash", "fish":
+        return name, nil
+    }
+    return "profile", nil
+}
+
+// shellConfigFor devolve o arquivo de inicialização do shell, respeitando
+// $ZDOTDIR (zsh) e $XDG_CONFIG_HOME (fish)
+func shellConfigFor(name string) (shellConfig, error) {
+    homeDir, err := os.UserHomeDir()
+    if err != nil {
+        return shellConfig{}, fmt.Errorf("não foi possível obter o diretório home do usuário: %w", err)
+    }
+    sc := shellConfig{name: name}
+    switch name {
+    case "zsh":
+        dir := os.Getenv("ZDOTDIR")
+        if dir == "" {
+            dir = homeDir
+        }
+        sc.rcPath = filepath.Join(dir, ".zshrc")
+    case "bash":
+        sc.rcPath = filepath.Join(homeDir, ".bashrc")
+    case "fish":
+        dir := os.Getenv("XDG_CONFIG_HOME")
+        if dir == "" {
+            dir = filepath.Join(homeDir, ".config")
+        }
+        sc.rcPath = filepath.Join(dir, "fish", "config.fish")
+    default:
+        sc.rcPath = filepath.Join(homeDir, ".profile")
+    }
+    return sc, nil
+}
+
+// binPath devolve o caminho absoluto de ./bin
+func binPath() string {
+    return filepath.Join(projectRoot, binDir)
+}
+
+func runSetupPath() error {
+    name, err := detectShell()
+    if err != nil {
+        return err
+    }
+    sc, err := shellConfigFor(name)
+    if err != nil {
+        return err
+    }
+    absBinPath := binPath()
+
+    if _, err := os.Stat(absBinPath); os.IsNotExist(err) {
+        log.Printf("Aviso: O diretório '%s' não existe. Criando para adicionar ao PATH.", absBinPath)
+        if err := os.MkdirAll(absBinPath, 0755); err != nil {
+            return fmt.Errorf("erro ao criar diretório '%s': %w", absBinPath, err)
+        }
+    }
+
+    log.Printf("Shell: %s (arquivo '%s')", sc.name, sc.rcPath)
+    pathLine := sc.pathLine(absBinPath)
+    block := []string{
+        blockStart,
+        fmt.Sprintf("# Gerenciado por fsgo -setupPath (%s); remova com fsgo -unsetupPath", time.Now().Format(time.RFC1123)),
+        pathLine,
+        blockEnd,
+    }
+
+    // O config.fish fica em um subdiretório que pode ainda não existir
+    if err := os.MkdirAll(filepath.Dir(sc.rcPath), 0755); err != nil {
+        return fmt.Errorf("erro ao criar diretório '%s': %w", filepath.Dir(sc.rcPath), err)
+    }
+
+    result, err := updateManagedBlock(sc.rcPath, block, pathLine)
+    if err != nil {
+        return err
+    }
+    switch result {
+    case blockUnchanged:
+        log.Printf("O caminho '%s' já está configurado em '%s'. Nenhuma alteração feita.", absBinPath, sc.rcPath)
+        return nil
+    case blockReplaced:
+        log.Printf("Bloco do fsgo em '%s' atualizado para '%s'.", sc.rcPath, absBinPath)
+    default:
+        log.Printf("Adicionado '%s' ao PATH em '%s'.", absBinPath, sc.rcPath)
+    }
+    log.Printf("Bloco gravado:\n%s", strings.Join(block, "\n"))
+    log.Println("IMPORTANTE: Para aplicar as mudanças, reinicie seu terminal ou execute: source", sc.rcPath)
+    return nil
+}
+
+// runUnsetupPath remove o bloco do -setupPath do arquivo do shell escolhido
+// com -shell ou, sem ele, dos arquivos de todos os shells conhecidos
+func runUnsetupPath() error {
+    names := knownShells
+    if *shellName != "" {
+        name, err := detectShell()
+        if err != nil {
+            return err
+        }
+        names = []string{name}
+    }
+
+    removed := 0
+    for _, name := range names {
+        sc, err := shellConfigFor(name)
+        if err != nil {
+            return err
+        }
+        result, err := updateManagedBlock(sc.rcPath, nil, "")
+        if err != nil {
+            return err
+        }
+        if result == blockRemoved {
+            log.Printf("Configuração do fsgo removida de '%s'.", sc.rcPath)
+            removed++
+        }
+    }
+    if removed == 0 {
+        log.Println("Nenhuma configuração do fsgo encontrada. Nenhuma alteração feita.")
+        return nil
+    }
+    log.Println("IMPORTANTE: O PATH dos terminais já abertos só muda quando eles forem reiniciados.")
+    return nil
+}
+
+// Resultados de updateManagedBlock
+const (
+    blockUnchanged = iota
+    blockAdded
+    blockReplaced
+    blockRemoved
+)
+
+// updateManagedBlock garante que path tenha exatamente um bloco do fsgo com as
+// linhas de block (ou nenhum, com block nil). Um bloco existente é reescrito no
+// mesmo lugar; sem bloco, ele é acrescentado ao final. Entradas no formato
+// antigo ("# fsgo (<data>)" + linha de PATH) são sempre removidas. Se o bloco
+// existente já contém wantLine, nada é reescrito (a data fica a da primeira vez).
+func updateManagedBlock(path string, block []string, wantLine string) (int, error) {
+    data, err := os.ReadFile(path)
+    if err != nil && !os.IsNotExist(err) {
+        return 0, fmt.Errorf("erro ao ler '%s': %w", path, err)
+    }
+    perms := os.FileMode(0644)
+    if info, err := os.Stat(path); err == nil {
+        perms = info.Mode().Perm()
+    }
+
+    var lines []string
+    if len(data) > 0 {
+        lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+    }
+
+    var kept []string
+    var current [][]string // conteúdo de cada bloco encontrado
+    insertAt := -1
+    legacy := 0
+    for i := 0; i < len(lines); i++ {
+        line := lines[i]
+        switch {
+        case strings.TrimSpace(line) == blockStart:
+            end := i + 1
+            for end < len(lines) && strings.TrimSpace(lines[end]) != blockEnd {
+                end++
+            }
+            if end == len(lines) {
+                return 0, fmt.Errorf("'%s' tem um '%s' sem o '%s' correspondente (linha %d); corrija o arquivo manualmente", path, blockStart, blockEnd, i+1)
+            }
+            current = append(current, lines[i:end+1])
+            if insertAt < 0 {
+                insertAt = len(kept)
+            }
+            i = end
+        case strings.HasPrefix(line, pathComment+" ("):
+            legacy++
+            if len(kept) > 0 && kept[len(kept)-1] == "" {
+                kept = kept[:len(kept)-1]
+            }
+            if i+1 < len(lines) && isPathLine(lines[i+1]) {
+                i++
+            }
+        default:
+            kept = append(kept, line)
+        }
+    }
+
+    // Um único bloco já correto e nada antigo para limpar: não mexe no arquivo
+    if block != nil && len(current) == 1 && legacy == 0 && containsLine(current[0], wantLine) {
+        return blockUnchanged, nil
+    }
+    if block == nil && len(current) == 0 && legacy == 0 {
+        return blockUnchanged, nil
+    }
+
+    result := blockRemoved
+    if block != nil {
+        if insertAt >= 0 {
+            result = blockReplaced
+        } else {
+            result = blockAdded
+            if len(kept) > 0 && kept[len(kept)-1] != "" {
+                kept = append(kept, "")
+            }
+            insertAt = len(kept)
+        }
+        kept = append(kept[:insertAt], append(append([]string(nil), block...), kept[insertAt:]...)...)
+    } else if insertAt > 0 && kept[insertAt-1] == "" {
+        // Remove também a linha em branco que o -setupPath coloca antes do bloco
+        kept = append(kept[:insertAt-1], kept[insertAt:]...)
+    }
+
+    content := strings.Join(kept, "\n")
+    if len(kept) > 0 {
+        content += "\n"
+    }
+    if err := os.WriteFile(path, []byte(content), perms); err != nil {
+        return 0, fmt.Errorf("erro ao escrever em '%s': %w", path, err)
+    }
+    return result, nil
+}
+
+func containsLine(lines []string, target string) bool {
+    for _, line := range lines {
+        if strings.TrimSpace(line) == target {
+            return true
+        }
+    }
+    return false
+}
+
+// isPathLine reconhece as linhas geradas por shellConfig.pathLine
+func isPathLine(line string) bool {
+    line = strings.TrimSpace(line)
+    return strings.HasPrefix(line, "export PATH=") || strings.HasPrefix(line, "fish_add_path ")
+}
+
+// -------------------- -doctor --------------------
+
+// doctorReport imprime cada verificação do -doctor e conta os problemas
+type doctorReport struct {
+    problems int
+}
+
+func (r *doctorReport) ok(format string, args ...interface{}) {
+    log.Printf("✔ "+format, args...)
+}
+
+func (r *doctorReport) fail(format string, args ...interface{}) {
+    log.Printf("✘ "+format, args...)
+    r.problems++
+}
+
+// runDoctor verifica a forma como as ferramentas chegam ao PATH: com uma
+// instalação por -install (há manifesto), o prefixo e os arquivos instalados;
+// sem ela, ./bin e o bloco do -setupPath no arquivo do shell
+func runDoctor() error {
+    report := &doctorReport{}
+    manifestPath, err := installManifestPath()
+    if err != nil {
+        return err
+    }
+    m, err := readInstallManifest(manifestPath)
+    if err != nil {
+        return err
+    }
+    if m != nil {
+        log.Printf("Instalação por -install encontrada (manifesto '%s').", manifestPath)
+        doctorInstall(report, m)
+    } else if err := doctorBin(report); err != nil {
+        return err
+    }
+
+    if report.problems > 0 {
+        return fmt.Errorf("%d problema(s) encontrado(s)", report.problems)
+    }
+    return nil
+}
+
+// doctorBin verifica se ./bin está no PATH deste processo (herdado do shell),
+// se o arquivo do shell tem o bloco apontando para ele e se cada ferramenta
+// encontrada no PATH é de fato a de ./bin
+func doctorBin(report *doctorReport) error {
+    absBinPath := binPath()
+    commands, _ := findCommands(projectRoot)
+    if _, err := os.Stat(absBinPath); err != nil {
+        report.fail("O diretório '%s' não existe; rode fsgo -buildAll.", absBinPath)
+    } else {
+        report.ok("Diretório de executáveis: %s", absBinPath)
+    }
+
+    inPath := dirInPath(absBinPath)
+    if inPath {
+        report.ok("'%s' está no PATH do shell atual.", absBinPath)
+    } else {
+        report.fail("'%s' NÃO está no PATH do shell atual (abra um novo terminal ou rode 'source' no arquivo abaixo).", absBinPath)
+    }
+
+    name, err := detectShell()
+    if err != nil {
+        return err
+    }
+    sc, err := shellConfigFor(name)
+    if err != nil {
+        return err
+    }
+    data, err := os.ReadFile(sc.rcPath)
+    switch {
+    case err != nil && !os.IsNotExist(err):
+        report.fail("Não foi possível ler '%s': %v", sc.rcPath, err)
+    case !strings.Contains(string(data), blockStart):
+        report.fail("'%s' (shell %s) não tem o bloco do fsgo; rode fsgo -setupPath.", sc.rcPath, sc.name)
+    case !strings.Contains(string(data), sc.pathLine(absBinPath)):
+        report.fail("O bloco do fsgo em '%s' aponta para outro diretório; rode fsgo -setupPath para atualizá-lo.", sc.rcPath)
+    default:
+        report.ok("'%s' (shell %s) tem o bloco do fsgo apontando para '%s'.", sc.rcPath, sc.name, absBinPath)
+    }
+
+    checkShadowed(report, commands, absBinPath, inPath)
+    return nil
+}
+
+// doctorInstall verifica se o prefixo do -install está no PATH e se cada arquivo
+// do manifesto continua como foi instalado (mesmo hash ou mesmo alvo do link)
+func doctorInstall(report *doctorReport, m *installManifest) {
+    inPath := dirInPath(m.Prefix)
+    if inPath {
+        report.ok("'%s' (prefixo do -install) está no PATH do shell atual.", m.Prefix)
+    } else {
+        report.fail("'%s' (prefixo do -install) NÃO está no PATH do shell atual.", m.Prefix)
+    }
+
+    var tools []string
+    intact := 0
+    for _, f := range m.Files {
+        tools = append(tools, f.Tool)
+        if _, err := os.Lstat(f.Path); err != nil {
+            report.fail("'%s' foi instalado mas não existe mais; rode fsgo -update.", f.Path)
+            continue
+        }
+        if f.Target != "" {
+            link, err := os.Readlink(f.Path)
+            switch {
+            case err != nil || link != f.Target:
+                report.fail("'%s' não é mais o link para '%s'.", f.Path, f.Target)
+            case !fileExists(f.Target):
+                report.fail("'%s' aponta para '%s', que não existe; rode fsgo -update.", f.Path, f.Target)
+            default:
+                intact++
+            }
+            continue
+        }
+        if hash, err := fileSHA256(f.Path); err != nil || hash != f.SHA256 {
+            report.fail("'%s' mudou desde a instalação; rode fsgo -update.", f.Path)
+            continue
+        }
+        intact++
+    }
+    if intact == len(m.Files) {
+        report.ok("Os %d arquivo(s) instalados em '%s' estão como o -install os deixou.", intact, m.Prefix)
+    }
+
+    checkShadowed(report, tools, m.Prefix, inPath)
+}
+
+// checkShadowed avisa quando uma ferramenta de mesmo nome vem antes de dir no
+// PATH (ou, com dir no PATH, quando ela não é encontrada)
+func checkShadowed(report *doctorReport, commands []string, dir string, inPath bool) {
+    for _, cmdName := range commands {
+        found, err := exec.LookPath(cmdName)
+        if err != nil {
+            if inPath {
+                report.fail("'%s' não foi encontrada no PATH (ainda não compilada?).", cmdName)
+            }
+            continue
+        }
+        if !sameDir(filepath.Dir(found), dir) {
+            report.fail("'%s' no PATH é '%s', não a de '%s'.", cmdName, found, dir)
+        }
+    }
+}
+
+func fileExists(path string) bool {
+    _, err := os.Stat(path)
+    return err == nil
+}
+
+// sameDir compara dois diretórios resolvendo links simbólicos
+func sameDir(a, b string) bool {
+    if filepath.Clean(a) == filepath.Clean(b) {
+        return true
+    }
+    ra, errA := filepath.EvalSymlinks(a)
+    rb, errB := filepath.EvalSymlinks(b)
+    return errA == nil && errB == nil && ra == rb
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "runtime"
+    "strings"
+    "testing"
+)
+
+func TestBuildStampUpToDate(t *testing.T) {
+    stamp := buildStamp{SourceHash: "abc", GoVersion: "go1.22.0", Version: "v1.0.0 1234567"}
+    tests := []struct {
+        name       string
+        sourceHash string
+        goVersion  string
+        want       bool
+    }{
+        {"mesmos fontes e mesmo Go", "abc", "go1.22.0", true},
+        {"fontes mudaram", "def", "go1.22.0", false},
+        {"Go mudou", "abc", "go1.23.0", false},
+        {"hash vazio não confere", "", "go1.22.0", false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := stamp.upToDate(tt.sourceHash, tt.goVersion); got != tt.want {
+                t.Errorf("upToDate(%q, %q) = %v, quer %v", tt.sourceHash, tt.goVersion, got, tt.want)
+            }
+        })
+    }
+}
+
+// A versão gravada no carimbo é só informativa: trocar de commit não recompila
+func TestBuildStampRoundTrip(t *testing.T) {
+    path := filepath.Join(t.TempDir(), ".tool.stamp")
+    want := buildStamp{SourceHash: "abc", GoVersion: "go1.22.0", Version: "v1.0.0 1234567"}
+    if err := writeBuildStamp(path, want); err != nil {
+        t.Fatal(err)
+    }
+    got, err := readBuildStamp(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got != want {
+        t.Errorf("readBuildStamp = %+v, quer %+v", got, want)
+    }
+    if !got.upToDate(want.SourceHash, want.GoVersion) {
+        t.Error("carimbo relido não está atualizado")
+    }
+}
+
+// writeModule cria um módulo mínimo: cmd/a usa internal/x, cmd/b é independente
+func writeModule(t *testing.T) string {
+    t.Helper()
+    root := t.TempDir()
+    files := map[string]string{
+        "go.mod":          "module exemplo\n\ngo 1.22\n",
+        "README.md":       "# exemplo\n",
+        "cmd/a/main.go":   "package main\n\nimport \"exemplo/internal/x\"\n\nfunc main() { x.F() }\n",
+        "cmd/b/main.go":   "package main\n\nfunc main() {}\n",
+        "internal/x/x.go": "package x\n\nfunc F() {}\n",
+        "internal/y/y.go": "package y\n\nfunc G() {}\n",
+    }
+    for name, content := range files {
+        writeFile(t, root, name, content)
+    }
+    return root
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+    t.Helper()
+    path := filepath.Join(root, filepath.FromSlash(name))
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// sourceHash de cmd/a muda exatamente quando algo que entra na compilação muda
+func TestSourceHash(t *testing.T) {
+    linux := buildTarget{goos: "linux", goarch: "amd64"}
+    windows := buildTarget{goos: "windows", goarch: "amd64"}
+    tests := []struct {
+        name        string
+        target      buildTarget
+        change      func(t *testing.T, root string)
+        wantChanged bool
+    }{
+        {
+            name:   "nada muda",
+            target: linux,
+            change: func(t *testing.T, root string) {},
+        },
+        {
+            name:   "README não conta",
+            target: linux,
+            change: func(t *testing.T, root string) { writeFile(t, root, "README.md", "outro\n") },
+        },
+        {
+            name:   "outro comando não conta",
+            target: linux,
+            change: func(t *testing.T, root string) {
+                writeFile(t, root, "cmd/b/main.go", "package main\n\nfunc main() { println() }\n")
+            },
+        },
+        {
+            name:   "pacote interno não importado não conta",
+            target: linux,
+            change: func(t *testing.T, root string) { writeFile(t, root, "internal/y/y.go", "package y\n\nfunc H() {}\n") },
+        },
+        {
+            name:   "testes não contam",
+            target: linux,
+            change: func(t *testing.T, root string) { writeFile(t, root, "cmd/a/main_test.go", "package main\n") },
+        },
+        {
+            name:   "o próprio comando",
+            target: linux,
+            change: func(t *testing.T, root string) {
+                writeFile(t, root, "cmd/a/main.go", "package main\n\nimport \"exemplo/internal/x\"\n\nfunc main() { x.F(); x.F() }\n")
+            },
+            wantChanged: true,
+        },
+        {
+            name:   "pacote interno importado",
+            target: linux,
+            change: func(t *testing.T, root string) {
+                writeFile(t, root, "internal/x/x.go", "package x\n\nfunc F() { println() }\n")
+            },
+            wantChanged: true,
+        },
+        {
+            name:   "arquivo de outra plataforma não conta",
+            target: linux,
+            change: func(t *testing.T, root string) { writeFile(t, root, "cmd/a/extra_windows.go", "package main\n") },
+        },
+        {
+            name:        "arquivo da plataforma alvo conta",
+            target:      windows,
+            change:      func(t *testing.T, root string) { writeFile(t, root, "cmd/a/extra_windows.go", "package main\n") },
+            wantChanged: true,
+        },
+        {
+            name:   "arquivo renomeado conta",
+            target: linux,
+            change: func(t *testing.T, root string) {
+                if err := os.Rename(filepath.Join(root, "internal/x/x.go"), filepath.Join(root, "internal/x/f.go")); err != nil {
+                    t.Fatal(err)
+                }
+            },
+            wantChanged: true,
+        },
+        {
+            name:        "go.mod conta",
+            target:      linux,
+            change:      func(t *testing.T, root string) { writeFile(t, root, "go.mod", "module exemplo\n\ngo 1.21\n") },
+            wantChanged: true,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root := writeModule(t)
+            before, err := sourceHash(root, "./cmd/a", tt.target)
+            if err != nil {
+                t.Fatalf("sourceHash: %v", err)
+            }
+            tt.change(t, root)
+            after, err := sourceHash(root, "./cmd/a", tt.target)
+            if err != nil {
+                t.Fatalf("sourceHash: %v", err)
+            }
+            if changed := before != after; changed != tt.wantChanged {
+                t.Errorf("hash mudou = %v, quer %v", changed, tt.wantChanged)
+            }
+        })
+    }
+}
+
+func TestSourceHashUnknownPackage(t *testing.T) {
+    if _, err := sourceHash(writeModule(t), "./cmd/nao-existe", buildTarget{}); err == nil {
+        t.Error("sourceHash: erro esperado para um pacote inexistente")
+    }
+}
+
+// updateManagedBlock reescreve os arquivos de inicialização dos usuários: tudo
+// fora do bloco do fsgo precisa sobrar exatamente como estava
+func TestUpdateManagedBlock(t *testing.T) {
+    const (
+        newLine = `export PATH="/novo/bin:$PATH"`
+        oldLine = `export PATH="/antigo/bin:$PATH"`
+    )
+    block := []string{blockStart, "# Gerenciado por fsgo -setupPath (hoje)", newLine, blockEnd}
+    blockText := strings.Join(block, "\n") + "\n"
+    oldBlock := blockStart + "\n# Gerenciado por fsgo -setupPath (ontem)\n" + oldLine + "\n" + blockEnd + "\n"
+    sameBlock := blockStart + "\n# Gerenciado por fsgo -setupPath (ontem)\n" + newLine + "\n" + blockEnd + "\n"
+
+    tests := []struct {
+        name       string
+        initial    *string // nil = o arquivo não existe
+        remove     bool
+        want       string
+        wantResult int
+        wantErr    bool
+    }{
+        {name: "arquivo novo", want: blockText, wantResult: blockAdded},
+        {name: "arquivo vazio", initial: ptr(""), want: blockText, wantResult: blockAdded},
+        {name: "acrescenta ao final com uma linha em branco", initial: ptr("alias ll='ls -l'\n"), want: "alias ll='ls -l'\n\n" + blockText, wantResult: blockAdded},
+        {name: "arquivo sem newline final", initial: ptr("alias ll='ls -l'"), want: "alias ll='ls -l'\n\n" + blockText, wantResult: blockAdded},
+        {name: "bloco já correto não é reescrito", initial: ptr("a\n\n" + sameBlock + "b\n"), want: "a\n\n" + sameBlock + "b\n", wantResult: blockUnchanged},
+        {name: "bloco antigo é trocado no mesmo lugar", initial: ptr("a\n\n" + oldBlock + "b\n"), want: "a\n\n" + blockText + "b\n", wantResult: blockReplaced},
+        {name: "blocos repetidos viram um", initial: ptr(oldBlock + "a\n" + sameBlock), want: blockText + "a\n", wantResult: blockReplaced},
+        {name: "marcadores com espaços", initial: ptr("  " + blockStart + "\n" + oldLine + "\n" + blockEnd + "  \n"), want: blockText, wantResult: blockReplaced},
+        {name: "formato antigo é removido", initial: ptr("a\n\n# fsgo (Mon, 01 Jan 2024)\n" + oldLine + "\n"), want: "a\n\n" + blockText, wantResult: blockAdded},
+        {name: "formato antigo do fish é removido", initial: ptr("a\n\n# fsgo (Mon, 01 Jan 2024)\nfish_add_path \"/antigo/bin\"\nb\n"), want: "a\nb\n\n" + blockText, wantResult: blockAdded},
+        {name: "bloco sem fim é erro", initial: ptr("a\n" + blockStart + "\n" + oldLine + "\n"), wantErr: true},
+        {name: "remove o bloco e a linha em branco antes dele", initial: ptr("a\n\n" + oldBlock + "b\n"), remove: true, want: "a\nb\n", wantResult: blockRemoved},
+        {name: "remove o formato antigo", initial: ptr("a\n\n# fsgo (Mon, 01 Jan 2024)\n" + oldLine + "\n"), remove: true, want: "a\n", wantResult: blockRemoved},
+        {name: "remover sem bloco não muda nada", initial: ptr("a\n# fsgo é ótimo\n"), remove: true, want: "a\n# fsgo é ótimo\n", wantResult: blockUnchanged},
+        {name: "remover o único conteúdo deixa o arquivo vazio", initial: ptr(oldBlock), remove: true, want: "", wantResult: blockRemoved},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), ".bashrc")
+            if tt.initial != nil {
+                if err := os.WriteFile(path, []byte(*tt.initial), 0600); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            wantBlock, wantLine := block, newLine
+            if tt.remove {
+                wantBlock, wantLine = nil, ""
+            }
+
+            result, err := updateManagedBlock(path, wantBlock, wantLine)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("updateManagedBlock: erro %v, quer erro = %v", err, tt.wantErr)
+            }
+            data, readErr := os.ReadFile(path)
+            if tt.wantErr {
+                // Com erro, o arquivo fica como estava
+                if string(data) != *tt.initial {
+                    t.Errorf("arquivo alterado apesar do erro:\n%s", data)
+                }
+                return
+            }
+            if result != tt.wantResult {
+                t.Errorf("resultado = %d, quer %d", result, tt.wantResult)
+            }
+            if readErr != nil {
+                t.Fatal(readErr)
+            }
+            if string(data) != tt.want {
+                t.Errorf("arquivo:\n%s\nquer:\n%s", data, tt.want)
+            }
+            // As permissões do arquivo do usuário são mantidas
+            if info, err := os.Stat(path); err == nil && tt.initial != nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+                t.Errorf("permissões = %v, quer %v", info.Mode().Perm(), os.FileMode(0600))
+            }
+        })
+    }
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,971 @@
+// list_files lista os arquivos de um diretório, com filtros por nome, glob,
+// tamanho, data e dono.
+package main
+
+import (
+    "bufio"
+    "crypto/sha256"
+    "encoding/csv"
+    "encoding/hex"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "io/fs"
+    "log"
+    "os"
+    "os/user"
+    "path/filepath"
+    "regexp"
+    "runtime"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "fsgo/internal/cliflag"
+    "fsgo/internal/version"
+    "fsgo/internal/walk"
+)
+
+func showHelp() {
+    fmt.Printf("%s %s: Lista os arquivos de um diretório.\n", filepath.Base(os.Args[0]), version.Version)
+    fmt.Println("Uso: extractglobal_getallfiles [opções] <diretório>")
+    fmt.Println("Opções:")
+    fmt.Println("  -pre <prefixo>        Listar apenas arquivos que começam com <prefixo>")
+    fmt.Println("  -post <sufixo>        Listar apenas arquivos que terminam com <sufixo>")
+    fmt.Println("  -glob <padrão>        Listar apenas arquivos que casam com o glob (aceita *, ?, [..], ** e {a,b}; repetível)")
+    fmt.Println("  -regex <regex>        Listar apenas arquivos que casam com a expressão regular")
+    fmt.Println("  -exclude <padrão>     Ignorar arquivos/diretórios que casam com o glob (repetível)")
+    fmt.Println("  -ignore-file <arq>    Ler regras no estilo .gitignore de <arq> (repetível)")
+    fmt.Println("  -match name|path      Aplicar -glob/-regex/-exclude ao nome base (padrão) ou ao caminho relativo")
+    fmt.Println("  -size [+-]N[ckMGT]    Tamanho maior (+), menor (-) ou igual a N (padrão em bytes)")
+    fmt.Println("  -mtime [+-]N[smhdw]   Modificado há mais (+), menos (-) ou exatamente N unidades (padrão em dias)")
+    fmt.Println("  -newer <arquivo>      Modificado depois de <arquivo>")
+    fmt.Println("  -type f|d|l           Tipo: arquivo regular, diretório ou link simbólico (aceita lista: f,l)")
+    fmt.Println("  -perm [-/]MODO        Permissões exatas (644), com todos os bits (-644) ou algum bit (/222)")
+    fmt.Println("  -empty                Apenas arquivos vazios ou diretórios vazios")
+    fmt.Println("  -owner <usuário>      Apenas entradas deste usuário (nome ou UID)")
+    fmt.Println("  -r, --recursive       Pesquisar também em subdiretórios")
+    fmt.Println("  -j <n>                Diretórios lidos em paralelo no modo recursivo (padrão: nº de CPUs)")
+    fmt.Println("  -L                    Seguir links simbólicos (com detecção de loops por dispositivo/inode)")
+    fmt.Println("  -P                    Nunca seguir links simbólicos; eles são listados como tipo l (padrão)")
+    fmt.Println("  -strict               Interromper no primeiro erro de acesso (por padrão, erros são reportados")
+    fmt.Println("                        no stderr, a busca continua e o código de saída é 1)")
+    fmt.Println("  -format <formato>     Formato de saída: lines (padrão), null, json, jsonl ou csv")
+    fmt.Println("  -fields <campos>      Campos exibidos, separados por vírgula: path, type, size, mtime, mode, sha256 (padrão: path)")
+    fmt.Println("  -version              Exibir a versão e sair")
+    fmt.Println("Exemplo:")
+    fmt.Println("  extractglobal_getallfiles /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -pre data_ /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -post .png /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -glob '*.{png,jpg}' -exclude 'thumb_*' /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -match path -regex '^src/.*_test\\.go$' /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -ignore-file .fsgoignore /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -glob '*.{png,jpg}' -size -1k -mtime -7d /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -type d -empty /path/to/dir")
+    fmt.Println("  extractglobal_getallfiles -r -format null -post .tmp /path/to/dir | xargs -0 rm")
+    fmt.Println("  extractglobal_getallfiles -r -format jsonl -fields path,size,sha256 /path/to/dir")
+    os.Exit(1)
+}
+
+func main() {
+    var prefix, suffix string
+    var recursive bool
+    var globs, excludes, ignoreFiles cliflag.StringList
+
+    flag.StringVar(&prefix, "pre", "", "Listar apenas arquivos com este prefixo")
+    flag.StringVar(&suffix, "post", "", "Listar apenas arquivos com este sufixo")
+    flag.Var(&globs, "glob", "Listar apenas arquivos que casam com este glob (repetível)")
+    regexFlag := flag.String("regex", "", "Listar apenas arquivos que casam com esta expressão regular")
+    flag.Var(&excludes, "exclude", "Ignorar arquivos/diretórios que casam com este glob (repetível)")
+    flag.Var(&ignoreFiles, "ignore-file", "Arquivo com regras no estilo .gitignore (repetível)")
+    matchMode := flag.String("match", "name", "Onde aplicar -glob/-regex/-exclude: name (nome base) ou path (caminho relativo)")
+    sizeFlag := flag.String("size", "", "Filtrar por tamanho: +N (maior), -N (menor) ou N (igual); unidades c, k, M, G, T")
+    mtimeFlag := flag.String("mtime", "", "Filtrar por idade da modificação: +N, -N ou N; unidades s, m, h, d, w")
+    newerFlag := flag.String("newer", "", "Listar apenas entradas modificadas depois deste arquivo")
+    typeFlag := flag.String("type", "", "Tipos a listar: f (arquivo), d (diretório), l (link simbólico)")
+    permFlag := flag.String("perm", "", "Filtrar por permissões em octal: MODO (exato), -MODO (todos os bits), /MODO (algum bit)")
+    emptyFlag := flag.Bool("empty", false, "Listar apenas arquivos vazios ou diretórios vazios")
+    ownerFlag := flag.String("owner", "", "Listar apenas entradas deste usuário (nome ou UID)")
+    // Flag sem valor para -r
+    boolRecursive := flag.Bool("r", false, "Pesquisar também em subdiretórios")
+    jobs := flag.Int("j", runtime.NumCPU(), "Número de diretórios lidos em paralelo no modo recursivo")
+    formatFlag := flag.String("format", "lines", "Formato de saída: lines, null, json, jsonl ou csv")
+    fieldsFlag := flag.String("fields", "path", "Campos exibidos: path, type, size, mtime, mode, sha256 (separados por vírgula)")
+    followLinks := flag.Bool("L", false, "Seguir links simbólicos (detecta loops por dispositivo/inode)")
+    noFollowLinks := flag.Bool("P", false, "Nunca seguir links simbólicos (padrão)")
+    flag.BoolVar(&strictMode, "strict", false, "Interromper no primeiro erro de acesso em vez de pular a entrada")
+    showVersion := flag.Bool("version", false, "Exibir a versão e sair")
+    flag.Usage = showHelp
+    flag.Parse()
+
+    if *showVersion {
+        fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+        return
+    }
+
+    // Pode também capturar --recursive manualmente
+    // Verificar se passamos --recursive sem -r
+    for _, arg := range os.Args {
+        if arg == "--recursive" {
+            *boolRecursive = true
+        }
+    }
+
+    recursive = *boolRecursive
+
+    // Resgatar argumentos restantes (diretório fica no primeiro)
+    args := flag.Args()
+    if len(args) < 1 {
+        showHelp()
+    }
+    dirPath := args[0]
+
+    // Verificar se o diretório existe
+    info, err := os.Stat(dirPath)
+    if err != nil || !info.IsDir() {
+        log.Fatalf("Erro: O diretório '%s' não existe ou não é diretório.\n", dirPath)
+    }
+
+    // Remover barra final
+    dirPath = strings.TrimRight(dirPath, "/")
+    if dirPath == "" {
+        dirPath = "/"
+    }
+
+    // Montar o filtro de nomes a partir das flags
+    filter := &nameFilter{prefix: prefix, suffix: suffix}
+    switch *matchMode {
+    case "name":
+    case "path":
+        filter.usePath = true
+    default:
+        log.Fatalf("Erro: Valor inválido para -match '%s' (use name ou path).\n", *matchMode)
+    }
+    for _, g := range globs {
+        re, err := globToRegexp(g)
+        if err != nil {
+            log.Fatalf("Erro: Glob inválido '%s': %v\n", g, err)
+        }
+        filter.globs = append(filter.globs, re)
+    }
+    if *regexFlag != "" {
+        re, err := regexp.Compile(*regexFlag)
+        if err != nil {
+            log.Fatalf("Erro: Expressão regular inválida '%s': %v\n", *regexFlag, err)
+        }
+        filter.regex = re
+    }
+    for _, e := range excludes {
+        re, err := globToRegexp(e)
+        if err != nil {
+            log.Fatalf("Erro: Padrão de exclusão inválido '%s': %v\n", e, err)
+        }
+        filter.excludes = append(filter.excludes, re)
+    }
+    for _, f := range ignoreFiles {
+        rules, err := loadIgnoreFile(f)
+        if err != nil {
+            log.Fatalf("Erro ao ler o arquivo de regras '%s': %v\n", f, err)
+        }
+        filter.ignore = append(filter.ignore, rules...)
+    }
+
+    if *followLinks && *noFollowLinks {
+        log.Fatalf("Erro: As opções -L e -P não podem ser usadas juntas.\n")
+    }
+
+    // Validar formato e campos de saída antes de percorrer o diretório
+    out, err := newOutputWriter(os.Stdout, *formatFlag, *fieldsFlag)
+    if err != nil {
+        log.Fatalf("Erro: %v\n", err)
+    }
+
+    // Montar o filtro de metadados
+    meta, err := newMetaFilter(*sizeFlag, *mtimeFlag, *newerFlag, *typeFlag, *permFlag, *ownerFlag, *emptyFlag)
+    if err != nil {
+        log.Fatalf("Erro: %v\n", err)
+    }
+
+    // Armazenar resultados
+    var matchedFiles []listedEntry
+
+    // consider aplica todos os filtros a uma entrada; só chama Lstat (d.Info)
+    // quando algum predicado de metadados precisa dele
+    consider := func(path, rel string, d fs.DirEntry) {
+        if !meta.wantsType(d.Type()) || !filter.includes(rel, d.Name(), d.IsDir()) {
+            return
+        }
+        if meta.needsInfo() {
+            f, err := d.Info()
+            if err != nil {
+                reportSkipped(path, err)
+                return
+            }
+            ok, err := meta.matches(path, f)
+            if err != nil {
+                reportSkipped(path, err)
+            }
+            if !ok {
+                return
+            }
+        }
+        matchedFiles = append(matchedFiles, listedEntry{path: path, d: d})
+    }
+
+    if recursive {
+        // Caminho recursivo
+        err := walk.Parallel(dirPath, *jobs, *followLinks, func(path string, d fs.DirEntry, err error) error {
+            if err != nil {
+                // Diretório ilegível ou loop de links: reporta e continua
+                reportSkipped(path, err)
+                return nil
+            }
+            rel := relativePath(dirPath, path)
+            // Diretórios excluídos não são percorridos
+            if d.IsDir() && filter.prunes(rel, d.Name()) {
+                return filepath.SkipDir
+            }
+            consider(path, rel, d)
+            return nil
+        })
+        if err != nil {
+            log.Fatalf("Erro ao percorrer o diretório '%s': %v\n", dirPath, err)
+        }
+    } else {
+        // Caminho não-recursivo
+        entries, err := os.ReadDir(dirPath)
+        if err != nil {
+            log.Fatalf("Erro ao ler o diretório '%s': %v\n", dirPath, err)
+        }
+        for _, entry := range entries {
+            name := entry.Name()
+            path := filepath.Join(dirPath, name)
+            // Com -L, um link para diretório é tratado como diretório (e não listado como arquivo)
+            if *followLinks && entry.Type()&fs.ModeSymlink != 0 {
+                info, err := os.Stat(path)
+                if err == nil {
+                    entry = fs.FileInfoToDirEntry(info)
+                } else if !os.IsNotExist(err) {
+                    // Link quebrado continua listado como link; outros erros são reportados
+                    reportSkipped(path, err)
+                    continue
+                }
+            }
+            consider(path, name, entry)
+        }
+    }
+
+    // Ordenar resultados
+    sort.Slice(matchedFiles, func(i, j int) bool {
+        return matchedFiles[i].path < matchedFiles[j].path
+    })
+
+    // Exibir resultados
+    if err := out.write(matchedFiles); err != nil {
+        log.Fatalf("Erro ao escrever a saída: %v\n", err)
+    }
+
+    // Qualquer entrada pulada torna o resultado incompleto
+    if skippedCount > 0 {
+        log.Printf("Aviso: %d entrada(s) não puderam ser lidas; o resultado está incompleto.\n", skippedCount)
+        os.Exit(1)
+    }
+    os.Exit(0)
+}
+
+// Erros de acesso encontrados durante a busca
+var (
+    strictMode   bool
+    skippedCount int
+)
+
+// reportSkipped registra no stderr uma entrada que não pôde ser lida.
+// Com -strict, interrompe a execução no primeiro erro.
+func reportSkipped(path string, err error) {
+    skippedCount++
+    if strictMode {
+        log.Fatalf("Erro: '%s': %v (interrompido por -strict)\n", path, err)
+    }
+    log.Printf("Erro: '%s': %v\n", path, err)
+}
+
+// relativePath devolve o caminho de path relativo à raiz listada, sempre com '/'
+func relativePath(root, path string) string {
+    rel, err := filepath.Rel(root, path)
+    if err != nil {
+        return filepath.ToSlash(path)
+    }
+    return filepath.ToSlash(rel)
+}
+
+// -------------------- Saída --------------------
+
+// listedEntry é uma entrada selecionada pelos filtros
+type listedEntry struct {
+    path string
+    d    fs.DirEntry
+}
+
+var validFields = map[string]bool{"path": true, "type": true, "size": true, "mtime": true, "mode": true, "sha256": true}
+
+// outputWriter formata as entradas selecionadas em um dos formatos suportados
+type outputWriter struct {
+    w      *bufio.Writer
+    format string
+    fields []string
+}
+
+func newOutputWriter(w io.Writer, format, fields string) (*outputWriter, error) {
+    switch format {
+    case "lines", "null", "json", "jsonl", "csv":
+    default:
+        return nil, fmt.Errorf("formato inválido '%s' (use lines, null, json, jsonl ou csv)", format)
+    }
+    ow := &outputWriter{w: bufio.NewWriter(w), format: format}
+    for _, f := range strings.Split(fields, ",") {
+        f = strings.TrimSpace(f)
+        if !validFields[f] {
+            return nil, fmt.Errorf("campo inválido '%s' em -fields (use path, type, size, mtime, mode, sha256)", f)
+        }
+        ow.fields = append(ow.fields, f)
+    }
+    return ow, nil
+}
+
+func (ow *outputWriter) write(entries []listedEntry) error {
+    var csvWriter *csv.Writer
+    switch ow.format {
+    case "csv":
+        csvWriter = csv.NewWriter(ow.w)
+        csvWriter.Write(ow.fields)
+    case "json":
+        ow.w.WriteString("[")
+    }
+
+    for i, e := range entries {
+        values, err := ow.values(e)
+        if err != nil {
+            reportSkipped(e.path, err)
+        }
+        switch ow.format {
+        case "lines", "null":
+            // Campos separados por tab; "null" termina cada registro com NUL (xargs -0)
+            strs := make([]string, len(values))
+            for j, v := range values {
+                strs[j] = fmt.Sprint(v)
+            }
+            ow.w.WriteString(strings.Join(strs, "\t"))
+            if ow.format == "null" {
+                ow.w.WriteByte(0)
+            } else {
+                ow.w.WriteByte('\n')
+            }
+        case "csv":
+            strs := make([]string, len(values))
+            for j, v := range values {
+                strs[j] = fmt.Sprint(v)
+            }
+            csvWriter.Write(strs)
+        case "json", "jsonl":
+            if ow.format == "json" {
+                if i > 0 {
+                    ow.w.WriteString(",")
+                }
+                ow.w.WriteString("\n  ")
+            }
+            if err := ow.writeJSONObject(values); err != nil {
+                return err
+            }
+            if ow.format == "jsonl" {
+                ow.w.WriteByte('\n')
+            }
+        }
+    }
+
+    switch ow.format {
+    case "csv":
+        csvWriter.Flush()
+        if err := csvWriter.Error(); err != nil {
+            return err
+        }
+    case "json":
+        if len(entries) > 0 {
+            ow.w.WriteString("\n")
+        }
+        ow.w.WriteString("]\n")
+    }
+    return ow.w.Flush()
+}
+
+// writeJSONObject escreve um objeto JSON mantendo a ordem de -fields
+func (ow *outputWriter) writeJSONObject(values []interface{}) error {
+    ow.w.WriteString("{")
+    for i, field := range ow.fields {
+        if i > 0 {
+            ow.w.WriteString(",")
+        }
+        key, _ := json.Marshal(field)
+        value, err := json.Marshal(values[i])
+        if err != nil {
+            return err
+        }
+        ow.w.Write(key)
+        ow.w.WriteString(":")
+        ow.w.Write(value)
+    }
+    ow.w.WriteString("}")
+    return nil
+}
+
+// values calcula os campos pedidos; metadados indisponíveis ficam com valor zero
+func (ow *outputWriter) values(e listedEntry) ([]interface{}, error) {
+    var info os.FileInfo
+    var firstErr error
+    values := make([]interface{}, len(ow.fields))
+    for i, field := range ow.fields {
+        if field != "path" && field != "type" && info == nil && firstErr == nil {
+            info, firstErr = e.d.Info()
+        }
+        switch field {
+        case "path":
+            values[i] = e.path
+        case "type":
+            // f, d, l (link não seguido) ou ? para outros tipos especiais
+            values[i] = string(fileTypeLetter(e.d.Type()))
+        case "size":
+            values[i] = int64(0)
+            if info != nil {
+                values[i] = info.Size()
+            }
+        case "mtime":
+            values[i] = ""
+            if info != nil {
+                values[i] = info.ModTime().Format(time.RFC3339)
+            }
+        case "mode":
+            values[i] = ""
+            if info != nil {
+                values[i] = info.Mode().String()
+            }
+        case "sha256":
+            values[i] = ""
+            if info != nil && info.Mode().IsRegular() {
+                sum, err := sha256File(e.path)
+                if err != nil {
+                    if firstErr == nil {
+                        firstErr = err
+                    }
+                    continue
+                }
+                values[i] = sum
+            }
+        }
+    }
+    return values, firstErr
+}
+
+func sha256File(path string) (string, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return "", err
+    }
+    defer file.Close()
+    hash := sha256.New()
+    if _, err := io.Copy(hash, file); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// -------------------- Filtro de nomes --------------------
+
+// nameFilter reúne todos os critérios baseados no nome do arquivo.
+// rel é o caminho relativo à raiz listada (com '/') e name é o nome base.
+type nameFilter struct {
+    prefix   string
+    suffix   string
+    globs    []*regexp.Regexp
+    regex    *regexp.Regexp
+    excludes []*regexp.Regexp
+    ignore   []ignoreRule
+    usePath  bool // -match path: aplica globs/regex/exclude ao caminho relativo
+}
+
+func (nf *nameFilter) target(rel, name string) string {
+    if nf.usePath {
+        return rel
+    }
+    return name
+}
+
+// includes informa se uma entrada deve ser listada de acordo com o nome
+func (nf *nameFilter) includes(rel, name string, isDir bool) bool {
+    if !strings.HasPrefix(name, nf.prefix) || !strings.HasSuffix(name, nf.suffix) {
+        return false
+    }
+    target := nf.target(rel, name)
+    if len(nf.globs) > 0 {
+        matched := false
+        for _, re := range nf.globs {
+            if re.MatchString(target) {
+                matched = true
+                break
+            }
+        }
+        if !matched {
+            return false
+        }
+    }
+    if nf.regex != nil && !nf.regex.MatchString(target) {
+        return false
+    }
+    for _, re := range nf.excludes {
+        if re.MatchString(target) {
+            return false
+        }
+    }
+    return !matchIgnoreRules(nf.ignore, rel, isDir)
+}
+
+// prunes informa se um diretório deve ser ignorado por inteiro (-exclude ou regras de ignore)
+func (nf *nameFilter) prunes(rel, name string) bool {
+    target := nf.target(rel, name)
+    for _, re := range nf.excludes {
+        if re.MatchString(target) {
+            return true
+        }
+    }
+    return matchIgnoreRules(nf.ignore, rel, true)
+}
+
+// -------------------- Filtro de metadados --------------------
+
+// metaFilter reúne os predicados no estilo do find; todos precisam ser satisfeitos
+type metaFilter struct {
+    types    string // letras aceitas de -type; vazio = tudo exceto diretórios
+    sizeCmp  int    // -1 menor, 0 igual, +1 maior
+    size     int64
+    hasSize  bool
+    mtimeCmp int
+    mtimeN   int64
+    mtimeU   time.Duration
+    hasMtime bool
+    newer    time.Time
+    hasNewer bool
+    permMode byte // 0 exato, '-' todos os bits, '/' algum bit
+    perm     os.FileMode
+    hasPerm  bool
+    empty    bool
+    uid      int // -1 = sem filtro de dono
+    now      time.Time
+}
+
+func newMetaFilter(size, mtime, newer, types, perm, owner string, empty bool) (*metaFilter, error) {
+    mf := &metaFilter{uid: -1, empty: empty, now: time.Now()}
+
+    if size != "" {
+        cmp, rest := splitSign(size)
+        n, err := parseSize(rest)
+        if err != nil {
+            return nil, fmt.Errorf("valor inválido para -size '%s': %w", size, err)
+        }
+        mf.sizeCmp, mf.size, mf.hasSize = cmp, n, true
+    }
+
+    if mtime != "" {
+        cmp, rest := splitSign(mtime)
+        n, unit, err := parseAge(rest)
+        if err != nil {
+            return nil, fmt.Errorf("valor inválido para -mtime '%s': %w", mtime, err)
+        }
+        mf.mtimeCmp, mf.mtimeN, mf.mtimeU, mf.hasMtime = cmp, n, unit, true
+    }
+
+    if newer != "" {
+        info, err := os.Stat(newer)
+        if err != nil {
+            return nil, fmt.Errorf("não foi possível acessar o arquivo de referência de -newer '%s': %w", newer, err)
+        }
+        mf.newer, mf.hasNewer = info.ModTime(), true
+    }
+
+    for _, t := range strings.Split(types, ",") {
+        switch t {
+        case "":
+        case "f", "d", "l":
+            mf.types += t
+        default:
+            return nil, fmt.Errorf("valor inválido para -type '%s' (use f, d ou l)", t)
+        }
+    }
+
+    if perm != "" {
+        mode := perm
+        if mode[0] == '-' || mode[0] == '/' {
+            mf.permMode = mode[0]
+            mode = mode[1:]
+        }
+        bits, err := strconv.ParseUint(mode, 8, 32)
+        if err != nil || bits > 07777 {
+            return nil, fmt.Errorf("valor inválido para -perm '%s' (use octal, ex.: 644, -644, /222)", perm)
+        }
+        mf.perm, mf.hasPerm = permBits(uint32(bits)), true
+    }
+
+    if owner != "" {
+        if !ownerSupported {
+            return nil, fmt.Errorf("-owner não é suportado neste sistema")
+        }
+        uid, err := lookupUID(owner)
+        if err != nil {
+            return nil, fmt.Errorf("usuário inválido para -owner '%s': %w", owner, err)
+        }
+        mf.uid = uid
+    }
+
+    return mf, nil
+}
+
+// wantsType verifica o tipo da entrada; sem -type mantém o comportamento antigo (tudo menos diretórios)
+func (mf *metaFilter) wantsType(mode fs.FileMode) bool {
+    if mf.types == "" {
+        return !mode.IsDir()
+    }
+    return strings.ContainsRune(mf.types, fileTypeLetter(mode))
+}
+
+// needsInfo informa se algum predicado precisa do os.FileInfo completo
+func (mf *metaFilter) needsInfo() bool {
+    return mf.hasSize || mf.hasMtime || mf.hasNewer || mf.hasPerm || mf.empty || mf.uid >= 0
+}
+
+// matches aplica os predicados de tamanho, datas, permissões, vazio e dono.
+// Só devolve erro quando não foi possível verificar se um diretório está vazio.
+func (mf *metaFilter) matches(path string, info os.FileInfo) (bool, error) {
+    if mf.hasSize && compareInt(info.Size(), mf.size) != mf.sizeCmp {
+        return false, nil
+    }
+    if mf.hasMtime {
+        age := int64(mf.now.Sub(info.ModTime()) / mf.mtimeU)
+        if compareInt(age, mf.mtimeN) != mf.mtimeCmp {
+            return false, nil
+        }
+    }
+    if mf.hasNewer && !info.ModTime().After(mf.newer) {
+        return false, nil
+    }
+    if mf.hasPerm {
+        mode := info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+        switch mf.permMode {
+        case '-':
+            if mode&mf.perm != mf.perm {
+                return false, nil
+            }
+        case '/':
+            if mf.perm != 0 && mode&mf.perm == 0 {
+                return false, nil
+            }
+        default:
+            if mode != mf.perm {
+                return false, nil
+            }
+        }
+    }
+    if mf.empty {
+        if info.IsDir() {
+            empty, err := isEmptyDir(path)
+            if err != nil || !empty {
+                return false, err
+            }
+        } else if !info.Mode().IsRegular() || info.Size() != 0 {
+            return false, nil
+        }
+    }
+    if mf.uid >= 0 {
+        uid, ok := fileUID(info)
+        if !ok || uid != mf.uid {
+            return false, nil
+        }
+    }
+    return true, nil
+}
+
+func fileTypeLetter(mode fs.FileMode) rune {
+    switch {
+    case mode&fs.ModeSymlink != 0:
+        return 'l'
+    case mode.IsDir():
+        return 'd'
+    case mode.IsRegular():
+        return 'f'
+    }
+    return '?'
+}
+
+// splitSign separa o prefixo '+' / '-' no estilo do find
+func splitSign(s string) (int, string) {
+    switch {
+    case strings.HasPrefix(s, "+"):
+        return 1, s[1:]
+    case strings.HasPrefix(s, "-"):
+        return -1, s[1:]
+    }
+    return 0, s
+}
+
+func compareInt(a, b int64) int {
+    switch {
+    case a < b:
+        return -1
+    case a > b:
+        return 1
+    }
+    return 0
+}
+
+// parseSize interpreta "10M", "1k", "512c" (sem unidade = bytes)
+func parseSize(s string) (int64, error) {
+    units := map[byte]int64{
+        'c': 1, 'b': 1,
+        'k': 1 << 10, 'K': 1 << 10,
+        'M': 1 << 20, 'G': 1 << 30, 'T': 1 << 40,
+    }
+    mult := int64(1)
+    if s != "" {
+        if m, ok := units[s[len(s)-1]]; ok {
+            mult = m
+            s = s[:len(s)-1]
+        }
+    }
+    n, err := strconv.ParseInt(s, 10, 64)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("use um número inteiro com unidade opcional (c, k, M, G, T)")
+    }
+    return n * mult, nil
+}
+
+// parseAge interpreta "7d", "12h", "30m" (sem unidade = dias)
+func parseAge(s string) (int64, time.Duration, error) {
+    units := map[byte]time.Duration{
+        's': time.Second,
+        'm': time.Minute,
+        'h': time.Hour,
+        'd': 24 * time.Hour,
+        'w': 7 * 24 * time.Hour,
+    }
+    unit := 24 * time.Hour
+    if s != "" {
+        if u, ok := units[s[len(s)-1]]; ok {
+            unit = u
+            s = s[:len(s)-1]
+        }
+    }
+    n, err := strconv.ParseInt(s, 10, 64)
+    if err != nil || n < 0 {
+        return 0, 0, fmt.Errorf("use um número inteiro com unidade opcional (s, m, h, d, w)")
+    }
+    return n, unit, nil
+}
+
+// permBits converte bits no formato Unix (ex.: 04755) para os.FileMode
+func permBits(bits uint32) os.FileMode {
+    mode := os.FileMode(bits & 0777)
+    if bits&04000 != 0 {
+        mode |= os.ModeSetuid
+    }
+    if bits&02000 != 0 {
+        mode |= os.ModeSetgid
+    }
+    if bits&01000 != 0 {
+        mode |= os.ModeSticky
+    }
+    return mode
+}
+
+func lookupUID(owner string) (int, error) {
+    if uid, err := strconv.Atoi(owner); err == nil {
+        return uid, nil
+    }
+    u, err := user.Lookup(owner)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(u.Uid)
+}
+
+func isEmptyDir(path string) (bool, error) {
+    dir, err := os.Open(path)
+    if err != nil {
+        return false, err
+    }
+    defer dir.Close()
+    names, err := dir.Readdirnames(1)
+    if err != nil && err != io.EOF {
+        return false, err
+    }
+    return len(names) == 0, nil
+}
+
+// -------------------- Regras estilo .gitignore --------------------
+
+type ignoreRule struct {
+    re       *regexp.Regexp
+    negate   bool // linha começando com '!'
+    dirOnly  bool // linha terminando com '/'
+    anchored bool // contém '/' no início ou no meio: casa com o caminho relativo
+}
+
+// loadIgnoreFile lê um arquivo de regras no estilo .gitignore.
+// Os padrões são relativos ao diretório listado.
+func loadIgnoreFile(path string) ([]ignoreRule, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    var rules []ignoreRule
+    scanner := bufio.NewScanner(file)
+    lineNum := 0
+    for scanner.Scan() {
+        lineNum++
+        line := strings.TrimRight(scanner.Text(), " \t\r")
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        var rule ignoreRule
+        if strings.HasPrefix(line, "!") {
+            rule.negate = true
+            line = line[1:]
+        } else if strings.HasPrefix(line, `\`) {
+            // "\#" e "\!" permitem padrões que começam com esses caracteres
+            line = line[1:]
+        }
+        if strings.HasSuffix(line, "/") {
+            rule.dirOnly = true
+            line = strings.TrimRight(line, "/")
+        }
+        if strings.Contains(line, "/") {
+            rule.anchored = true
+            line = strings.TrimPrefix(line, "/")
+        }
+        if line == "" {
+            continue
+        }
+        re, err := globToRegexp(line)
+        if err != nil {
+            return nil, fmt.Errorf("linha %d: %w", lineNum, err)
+        }
+        rule.re = re
+        rules = append(rules, rule)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return rules, nil
+}
+
+// matchIgnoreRules aplica as regras em ordem; a última que casar decide
+func matchIgnoreRules(rules []ignoreRule, rel string, isDir bool) bool {
+    ignored := false
+    name := rel
+    if i := strings.LastIndex(rel, "/"); i >= 0 {
+        name = rel[i+1:]
+    }
+    for _, rule := range rules {
+        if rule.dirOnly && !isDir {
+            continue
+        }
+        target := name
+        if rule.anchored {
+            target = rel
+        }
+        if rule.re.MatchString(target) {
+            ignored = !rule.negate
+        }
+    }
+    return ignored
+}
+
+// -------------------- Globs --------------------
+
+// globToRegexp converte um glob em uma regex ancorada.
+// Suporta: * (qualquer coisa exceto '/'), ? (um caractere exceto '/'),
+// [abc] / [!abc] (classes), ** (qualquer número de diretórios) e {a,b} (alternativas).
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+    var sb strings.Builder
+    sb.WriteString("^")
+    depth := 0 // nível de chaves {} abertas
+    for i := 0; i < len(glob); i++ {
+        c := glob[i]
+        switch c {
+        case '\\':
+            if i+1 >= len(glob) {
+                return nil, fmt.Errorf("'\\' no final do padrão")
+            }
+            i++
+            sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+        case '*':
+            if i+1 < len(glob) && glob[i+1] == '*' {
+                // "**/" casa zero ou mais diretórios; "**" no final casa tudo
+                i++
+                if i+1 < len(glob) && glob[i+1] == '/' {
+                    i++
+                    sb.WriteString("(?:.*/)?")
+                } else {
+                    sb.WriteString(".*")
+                }
+            } else {
+                sb.WriteString("[^/]*")
+            }
+        case '?':
+            sb.WriteString("[^/]")
+        case '[':
+            end := strings.IndexByte(glob[i+1:], ']')
+            if end < 0 {
+                return nil, fmt.Errorf("'[' sem ']' correspondente")
+            }
+            class := glob[i+1 : i+1+end]
+            if end == 0 {
+                // "[]...]": o ']' logo após '[' faz parte da classe
+                next := strings.IndexByte(glob[i+2:], ']')
+                if next < 0 {
+                    return nil, fmt.Errorf("'[' sem ']' correspondente")
+                }
+                class = glob[i+1 : i+2+next]
+                end = next + 1
+            }
+            if strings.HasPrefix(class, "!") {
+                class = "^" + class[1:]
+            }
+            sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+            i += end + 1
+        case '{':
+            depth++
+            sb.WriteString("(?:")
+        case '}':
+            if depth == 0 {
+                sb.WriteString(`\}`)
+                continue
+            }
+            depth--
+            sb.WriteString(")")
+        case ',':
+            if depth > 0 {
+                sb.WriteString("|")
+            } else {
+                sb.WriteString(",")
+            }
+        default:
+            sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+        }
+    }
+    if depth > 0 {
+        return nil, fmt.Errorf("'{' sem '}' correspondente")
+    }
+    sb.WriteString("$")
+    return regexp.Compile(sb.String())
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+    tests := []struct {
+        glob    string
+        match   []string
+        noMatch []string
+    }{
+        {"*.go", []string{"main.go", ".go"}, []string{"a/main.go", "main.go.bak", "main_go"}},
+        {"?.txt", []string{"a.txt"}, []string{"ab.txt", "/.txt", ".txt"}},
+        {"**/*.go", []string{"a.go", "x/a.go", "x/y/z/a.go"}, []string{"a.go/x", "x/a.goo"}},
+        {"src/**", []string{"src/", "src/a", "src/a/b"}, []string{"src", "lib/a"}},
+        {"a/**/b", []string{"a/b", "a/x/b", "a/x/y/b"}, []string{"a/xb", "ab"}},
+        {"[abc].txt", []string{"a.txt", "c.txt"}, []string{"d.txt", "ab.txt"}},
+        {"[!abc].txt", []string{"d.txt"}, []string{"a.txt"}},
+        {"[a-c]*", []string{"b", "cxx"}, []string{"d", "-"}},
+        {"[]a]", []string{"]", "a"}, []string{"b"}},
+        {`[\]`, []string{`\`}, []string{"a"}},
+        {"*.{go,md}", []string{"a.go", "README.md"}, []string{"a.txt", "a.{go,md}"}},
+        {"{a,b{c,d}}", []string{"a", "bc", "bd"}, []string{"b", "ac"}},
+        {"a,b", []string{"a,b"}, []string{"a", "b"}},
+        {"a}", []string{"a}"}, []string{"a"}},
+        {`\*.go`, []string{"*.go"}, []string{"a.go"}},
+        {"a+b(1).txt", []string{"a+b(1).txt"}, []string{"aab1.txt"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.glob, func(t *testing.T) {
+            re, err := globToRegexp(tt.glob)
+            if err != nil {
+                t.Fatalf("globToRegexp(%q): %v", tt.glob, err)
+            }
+            for _, s := range tt.match {
+                if !re.MatchString(s) {
+                    t.Errorf("%q deveria casar com %q (regex %s)", s, tt.glob, re)
+                }
+            }
+            for _, s := range tt.noMatch {
+                if re.MatchString(s) {
+                    t.Errorf("%q não deveria casar com %q (regex %s)", s, tt.glob, re)
+                }
+            }
+        })
+    }
+}
+
+func TestGlobToRegexpErrors(t *testing.T) {
+    tests := []struct {
+        glob    string
+        wantErr string
+    }{
+        {`a\`, "'\\' no final do padrão"},
+        {"[abc", "'[' sem ']' correspondente"},
+        {"[]", "'[' sem ']' correspondente"},
+        {"{a,b", "'{' sem '}' correspondente"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.glob, func(t *testing.T) {
+            _, err := globToRegexp(tt.glob)
+            if err == nil {
+                t.Fatalf("globToRegexp(%q): erro esperado", tt.glob)
+            }
+            if !strings.Contains(err.Error(), tt.wantErr) {
+                t.Errorf("globToRegexp(%q): erro %q, quer algo com %q", tt.glob, err, tt.wantErr)
+            }
+        })
+    }
+}
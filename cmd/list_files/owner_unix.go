@@ -0,0 +1,20 @@
+//go:build !windows
+
+package main
+
+import (
+    "os"
+    "syscall"
+)
+
+// ownerSupported diz se -owner pode ser usado neste sistema
+const ownerSupported = true
+
+// fileUID devolve o UID do dono da entrada
+func fileUID(info os.FileInfo) (int, bool) {
+    stat, ok := info.Sys().(*syscall.Stat_t)
+    if !ok {
+        return 0, false
+    }
+    return int(stat.Uid), true
+}
@@ -0,0 +1,10 @@
+package main
+
+import "os"
+
+// No Windows o dono é um SID, não um UID: -owner não é suportado
+const ownerSupported = false
+
+func fileUID(info os.FileInfo) (int, bool) {
+    return 0, false
+}
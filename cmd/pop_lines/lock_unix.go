@@ -0,0 +1,76 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile espera até obter o flock do arquivo (compartilhado ou exclusivo);
+// o lock é liberado quando o arquivo é fechado
+func lockFile(file *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	return syscall.Flock(int(file.Fd()), how)
+}
+
+// preserveOwner dá ao temporário o dono e o grupo do original. Sem permissão
+// para isso (por exemplo, em uma fila compartilhada por vários usuários) o
+// erro é devolvido e o temporário continua com o dono atual.
+func preserveOwner(tmpPath string, locked *os.File) error {
+	info, err := locked.Stat()
+	if err != nil {
+		return err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	tmpInfo, err := os.Stat(tmpPath)
+	if err != nil {
+		return err
+	}
+	if tmpSt, ok := tmpInfo.Sys().(*syscall.Stat_t); ok && tmpSt.Uid == st.Uid && tmpSt.Gid == st.Gid {
+		return nil
+	}
+	return os.Chown(tmpPath, int(st.Uid), int(st.Gid))
+}
+
+// checkReplace verifica antes de qualquer gravação se o rename sobre path será
+// permitido: em um diretório com sticky bit (como o /tmp) só o dono do arquivo
+// ou do diretório pode substituí-lo.
+func checkReplace(path string, locked *os.File) error {
+	dir := filepath.Dir(path)
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if dirInfo.Mode()&os.ModeSticky == 0 {
+		return nil
+	}
+	info, err := locked.Stat()
+	if err != nil {
+		return err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	dirSt, dirOk := dirInfo.Sys().(*syscall.Stat_t)
+	if !ok || !dirOk {
+		return nil
+	}
+	uid := uint32(os.Geteuid())
+	if uid == 0 || st.Uid == uid || dirSt.Uid == uid {
+		return nil
+	}
+	return fmt.Errorf("'%s' pertence a outro usuário e o diretório '%s' tem o sticky bit: o arquivo não pode ser substituído", path, dir)
+}
+
+// replaceLocked substitui path por tmpPath com um rename atômico. No Unix o
+// rename funciona com o original ainda aberto, então o lock vale até o fim.
+func replaceLocked(tmpPath, path string, locked *os.File) error {
+	return os.Rename(tmpPath, path)
+}
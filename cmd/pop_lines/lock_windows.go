@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var procLockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+
+const lockfileExclusiveLock = 0x2
+
+// lockFile espera até obter o lock do arquivo inteiro com LockFileEx (o
+// equivalente ao flock); o lock é liberado quando o arquivo é fechado
+func lockFile(file *os.File, exclusive bool) error {
+	var flags uintptr
+	if exclusive {
+		flags = lockfileExclusiveLock
+	}
+	var overlapped syscall.Overlapped
+	r1, _, err := procLockFileEx.Call(file.Fd(), flags, 0, uintptr(^uint32(0)), uintptr(^uint32(0)), uintptr(unsafe.Pointer(&overlapped)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// preserveOwner não faz nada no Windows: o arquivo novo herda as permissões
+// (ACL) do diretório
+func preserveOwner(tmpPath string, locked *os.File) error {
+	return nil
+}
+
+// checkReplace não tem verificações extras no Windows
+func checkReplace(path string, locked *os.File) error {
+	return nil
+}
+
+// replaceLocked substitui path por tmpPath. O Windows não permite renomear
+// sobre um arquivo aberto, então o original (e o seu lock) é fechado antes:
+// entre o fechamento e o rename outro processo pode ler a versão antiga.
+func replaceLocked(tmpPath, path string, locked *os.File) error {
+	locked.Close()
+	return os.Rename(tmpPath, path)
+}
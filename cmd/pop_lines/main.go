@@ -0,0 +1,1082 @@
+// pop_lines exibe ou remove as linhas de um arquivo que correspondem a uma
+// expressão regular.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath" // Nome base do programa e diretório dos arquivos temporários
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"fsgo/internal/cliflag"
+	"fsgo/internal/prompt"
+	"fsgo/internal/textdiff"
+	"fsgo/internal/version"
+)
+
+// Define as flags fora de main para que a descrição esteja disponível para flag.Usage
+var (
+	removeMatches = flag.Bool("R", false, "Remove linhas que correspondem à regex do arquivo (sobrescreve o original)")
+	invertMatch   = flag.Bool("v", false, "Inverte a seleção: considera as linhas que NÃO correspondem à regex")
+	lineNumbers   = flag.Bool("n", false, "Prefixa cada linha exibida com o seu número no arquivo")
+	countOnly     = flag.Bool("c", false, "Exibe apenas a quantidade de linhas selecionadas")
+	onlyMatching  = flag.Bool("o", false, "Exibe apenas a parte de cada linha que corresponde à regex")
+	afterContext  = flag.Int("A", 0, "Exibe `N` linhas de contexto depois de cada linha selecionada")
+	beforeContext = flag.Int("B", 0, "Exibe `N` linhas de contexto antes de cada linha selecionada")
+	context       = flag.Int("C", 0, "Exibe `N` linhas de contexto antes e depois de cada linha selecionada")
+	plainOutput   = flag.Bool("plain", false, "Saída sem banners; mensagens informativas vão para o stderr (para pipelines)")
+	popTarget     = flag.String("to", "", "Move as linhas selecionadas para este `arquivo` (implica -R)")
+	truncTarget   = flag.Bool("truncate", false, "Com -to, esvazia o arquivo de destino antes de gravar em vez de acrescentar ao final (só se alguma linha for retirada)")
+	headCount     = flag.Int("head", 0, "Retira do arquivo as primeiras `N` linhas (ou as N primeiras que correspondem aos padrões de -e/-f)")
+	tailCount     = flag.Int("tail", 0, "Retira do arquivo as últimas `N` linhas (ou as N últimas que correspondem aos padrões de -e/-f)")
+	fixedStrings  = flag.Bool("F", false, "Trata os padrões como texto literal (busca Aho-Corasick, rápida com milhares de padrões)")
+	wholeLine     = flag.Bool("x", false, "O padrão precisa corresponder à linha inteira")
+	recursive     = flag.Bool("r", false, "Processa recursivamente os arquivos dos diretórios informados")
+	parallelJobs  = flag.Int("j", runtime.NumCPU(), "Número de arquivos processados em paralelo")
+	diffMode      = flag.Bool("diff", false, "Exibe um diff unificado do arquivo (original x sem as linhas selecionadas) em vez das linhas")
+	diffContext   = flag.Int("U", 3, "Exibe `N` linhas de contexto em cada trecho do -diff")
+	confirmWrite  = flag.Bool("confirm", false, "Exibe o diff e pergunta antes de reescrever cada arquivo (com -R, -to, -head ou -tail)")
+	patternFiles  cliflag.StringList
+	patternFlags  cliflag.StringList
+
+	showVersion = flag.Bool("version", false, "Exibe a versão e sai")
+)
+
+func init() {
+	flag.Var(&patternFlags, "e", "Padrão a procurar (repetível); substitui o argumento <regex>")
+	flag.Var(&patternFiles, "f", "Lê os padrões deste `arquivo`, um por linha (linhas vazias são ignoradas; repetível)")
+}
+
+func main() {
+	// Define a função de Usage personalizada ANTES de flag.Parse()
+	flag.Usage = func() {
+		// Usa flag.CommandLine.Output() que por padrão é os.Stderr
+		output := flag.CommandLine.Output()
+		// Nome do executável (requer "path/filepath")
+		progName := filepath.Base(os.Args[0])
+
+		fmt.Fprintf(output, "%s %s: Exibe ou remove linhas de um arquivo que correspondem a uma expressão regular (regex).\n\n", progName, version.Version)
+		fmt.Fprintf(output, "Uso: %s [opções] <regex> <arquivo> [arquivo...]\n", progName)
+		fmt.Fprintf(output, "     %s -e <padrão> [-e <padrão>...] | -f <arquivo_padrões> [opções] <arquivo> [arquivo...]\n", progName)
+		fmt.Fprintf(output, "     %s -head N|-tail N [-e <padrão>...] [opções] <arquivo> [arquivo...]\n\n", progName)
+		fmt.Fprintf(output, "Argumentos:\n")
+		fmt.Fprintf(output, "  <regex>    A expressão regular Go (estilo PCRE) para procurar nas linhas.\n")
+		fmt.Fprintf(output, "             Lembre-se de usar aspas (' ') se a regex contiver espaços ou caracteres especiais do shell.\n")
+		fmt.Fprintf(output, "  <arquivo>  O caminho para o arquivo a ser processado (ou diretório, com -r).\n")
+		fmt.Fprintf(output, "             Com vários arquivos, cada um é processado e reescrito de forma independente,\n")
+		fmt.Fprintf(output, "             em paralelo (-j), com contagem por arquivo e um total no final.\n\n")
+		fmt.Fprintf(output, "Opções:\n")
+		// Imprime as descrições padrão das flags definidas (neste caso, -R)
+		flag.PrintDefaults()
+		fmt.Fprintf(output, "\nComportamento Padrão:\n")
+		fmt.Fprintf(output, "  Por padrão, o programa apenas exibe as linhas que correspondem à <regex> no terminal (stdout).\n")
+		fmt.Fprintf(output, "  O arquivo original não é modificado a menos que a opção -R seja usada.\n")
+		fmt.Fprintf(output, "  Com -head/-tail as linhas são retiradas do arquivo (fila de trabalho); não há <regex> posicional:\n")
+		fmt.Fprintf(output, "  todos os argumentos são arquivos e, para retirar só as linhas que casam, use -e ou -f.\n")
+		fmt.Fprintf(output, "  Toda alteração do arquivo é feita sob um lock (flock), então vários processos podem\n")
+		fmt.Fprintf(output, "  retirar linhas do mesmo arquivo ao mesmo tempo sem receber a mesma linha duas vezes.\n\n")
+		fmt.Fprintf(output, "Exemplos:\n")
+		fmt.Fprintf(output, "  # Exibir todas as linhas contendo 'WARN' ou 'ERROR' em app.log\n")
+		fmt.Fprintf(output, "  %s '(WARN|ERROR)' app.log\n\n", progName)
+		fmt.Fprintf(output, "  # Remover todas as linhas em branco (ou que só contêm espaços) de data.txt\n")
+		fmt.Fprintf(output, "  %s -R '^\\s*$' data.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Contar as linhas que NÃO contêm 'OK'\n")
+		fmt.Fprintf(output, "  %s -v -c 'OK' results.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Exibir erros com 2 linhas de contexto e números de linha, sem banners\n")
+		fmt.Fprintf(output, "  %s -plain -n -C 2 'ERROR' app.log | less\n\n", progName)
+		fmt.Fprintf(output, "  # Mover as linhas 'DONE' de queue.txt para o final de done.txt\n")
+		fmt.Fprintf(output, "  %s -to done.txt 'DONE' queue.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Worker de fila: retirar os próximos 10 jobs de jobs.txt\n")
+		fmt.Fprintf(output, "  %s -plain -head 10 jobs.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Remover de hosts.txt todas as linhas listadas (literalmente) em bloqueados.txt\n")
+		fmt.Fprintf(output, "  %s -R -F -x -f bloqueados.txt hosts.txt\n\n", progName)
+		fmt.Fprintf(output, "  # Ver o que -R removeria (diff unificado) e depois remover pedindo confirmação\n")
+		fmt.Fprintf(output, "  %s -diff 'DEBUG' app.log\n", progName)
+		fmt.Fprintf(output, "  %s -R -confirm 'DEBUG' app.log\n\n", progName)
+		fmt.Fprintf(output, "  # Remover linhas 'DEBUG' de vários logs (e de todos os arquivos sob logs/antigos)\n")
+		fmt.Fprintf(output, "  %s -R 'DEBUG' logs/*.log\n", progName)
+		fmt.Fprintf(output, "  %s -R -r 'DEBUG' logs/antigos\n", progName)
+	}
+
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+		return
+	}
+
+	if *headCount < 0 || *tailCount < 0 {
+		log.Fatalf("Erro: Os valores de -head e -tail devem ser positivos.\n")
+	}
+	if *headCount > 0 && *tailCount > 0 {
+		log.Fatalf("Erro: As opções -head e -tail não podem ser usadas juntas.\n")
+	}
+	// Modo fila: -head/-tail retiram linhas do arquivo e os padrões (só por -e/-f) são opcionais
+	queueMode := *headCount > 0 || *tailCount > 0
+
+	// Padrões vindos de -e/-f substituem o argumento posicional <regex>
+	patterns := []string(patternFlags)
+	for _, path := range patternFiles {
+		filePatterns, err := readPatternFile(path)
+		if err != nil {
+			log.Fatalf("Erro ao ler o arquivo de padrões '%s': %v\n", path, err)
+		}
+		patterns = append(patterns, filePatterns...)
+	}
+	explicitPatterns := len(patternFlags) > 0 || len(patternFiles) > 0
+
+	// Verifica se os argumentos obrigatórios (regex e arquivo) foram fornecidos
+	minArgs := 2
+	if queueMode || explicitPatterns {
+		minArgs = 1
+	}
+	if flag.NArg() < minArgs {
+		fmt.Fprintf(flag.CommandLine.Output(), "Erro: Os argumentos <regex> e <arquivo> são obrigatórios.\n\n")
+		flag.Usage() // Mostra a mensagem de uso completa
+		os.Exit(1)   // Sai com código de erro
+	}
+
+	// Sem -e/-f, o primeiro argumento é a regex. O modo fila não tem <regex>
+	// posicional (todos os argumentos são arquivos); para filtrar, use -e/-f.
+	fileArgs := flag.Args()
+	if !explicitPatterns && !queueMode {
+		patterns = []string{flag.Arg(0)}
+		fileArgs = fileArgs[1:]
+	}
+
+	// Compila os padrões - log.Fatalf é apropriado aqui, pois o programa não pode continuar
+	// Sem padrão (apenas no modo fila), todas as linhas são candidatas
+	var matcher lineMatcher
+	if explicitPatterns || len(patterns) > 0 || !queueMode {
+		var err error
+		matcher, err = newLineMatcher(patterns, *fixedStrings, *wholeLine)
+		if err != nil {
+			log.Fatalf("Erro: Expressão regular inválida: %v\n", err)
+		}
+	} else if *onlyMatching {
+		log.Fatalf("Erro: A opção -o exige um padrão (no modo fila, -e ou -f).\n")
+	}
+	if queueMode {
+		*removeMatches = true
+	}
+
+	// Contexto: -C define -A e -B quando eles não foram informados
+	if *context > 0 {
+		if *afterContext == 0 {
+			*afterContext = *context
+		}
+		if *beforeContext == 0 {
+			*beforeContext = *context
+		}
+	}
+	if *onlyMatching && *invertMatch {
+		log.Fatalf("Erro: As opções -o e -v não podem ser usadas juntas.\n")
+	}
+	// -to é um "pop" de verdade: as linhas saem do arquivo e vão para o destino
+	if *popTarget != "" {
+		*removeMatches = true
+	} else if *truncTarget {
+		log.Fatalf("Erro: A opção -truncate só pode ser usada junto com -to.\n")
+	}
+
+	// -confirm pergunta antes de reescrever, então exige um modo que reescreva o
+	// arquivo; o diff é sempre exibido antes da pergunta
+	if *confirmWrite {
+		if !*removeMatches {
+			log.Fatalf("Erro: A opção -confirm só pode ser usada junto com -R, -to, -head ou -tail.\n")
+		}
+		*diffMode = true
+	}
+	if *diffMode && *countOnly {
+		log.Fatalf("Erro: As opções -diff e -c não podem ser usadas juntas.\n")
+	}
+
+	// Expande diretórios (com -r) na lista de arquivos a processar
+	files, err := expandFileArgs(fileArgs, *recursive)
+	if err != nil {
+		log.Fatalf("Erro: %v\n", err)
+	}
+	if len(files) == 0 {
+		log.Fatalf("Erro: Nenhum arquivo encontrado para processar.\n")
+	}
+	// Nos modos que reescrevem (-R, -to, -head, -tail) todos os arquivos precisam
+	// existir antes de qualquer um ser reescrito: um argumento trocado (como uma
+	// regex passada no modo fila, que não tem <regex> posicional) não pode
+	// deixar os outros arquivos alterados.
+	if *removeMatches {
+		for _, path := range files {
+			if _, err := os.Stat(path); err != nil {
+				if queueMode && !explicitPatterns && path == fileArgs[0] && os.IsNotExist(err) {
+					log.Fatalf("Erro: Arquivo não encontrado '%s'. Com -head/-tail todos os argumentos são arquivos; para retirar só as linhas que casam com um padrão, use -e '%s'.\n", path, path)
+				}
+				if os.IsNotExist(err) {
+					log.Fatalf("Erro: Arquivo não encontrado '%s'. Nenhum arquivo foi alterado.\n", path)
+				}
+				log.Fatalf("Erro: Não foi possível acessar '%s': %v. Nenhum arquivo foi alterado.\n", path, err)
+			}
+		}
+	}
+	multi := len(files) > 1 || *recursive
+
+	// Mensagens informativas vão para o stderr no modo -plain, para não poluir pipelines
+	info := os.Stdout
+	if *plainOutput {
+		info = os.Stderr
+	}
+
+	stdout := bufio.NewWriter(os.Stdout)
+
+	// Um único arquivo: mesma saída de sempre, escrita diretamente no stdout
+	if !multi {
+		matchCount, err := processFile(files[0], matcher, stdout, info, false)
+		stdout.Flush()
+		if err != nil {
+			log.Fatalf("Erro: %v\n", err)
+		}
+		if *countOnly {
+			fmt.Println(matchCount)
+		}
+		return
+	}
+
+	// Vários arquivos: cada um é processado (e reescrito) de forma independente por
+	// um pool de workers; a saída de cada arquivo vai para um temporário (para a
+	// memória não crescer com o número de linhas exibidas) e é copiada para o
+	// stdout na ordem dos argumentos. As mensagens informativas são poucas
+	// linhas por arquivo e ficam em memória.
+	type fileResult struct {
+		out     *os.File
+		info    bytes.Buffer
+		matches int
+		err     error
+		done    chan struct{}
+	}
+	results := make([]*fileResult, len(files))
+	for i := range results {
+		results[i] = &fileResult{done: make(chan struct{})}
+	}
+	jobs := make(chan int)
+	workers := *parallelJobs
+	if workers < 1 {
+		workers = 1
+	}
+	// Com -confirm cada arquivo é perguntado em sequência, com o diff escrito
+	// diretamente no stdout antes da pergunta
+	if *confirmWrite {
+		for i, res := range results {
+			res.matches, res.err = processFile(files[i], matcher, stdout, info, true)
+			stdout.Flush()
+			close(res.done)
+		}
+		close(jobs)
+		workers = 0
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				res := results[i]
+				tmp, err := os.CreateTemp("", "pop_lines-*.out")
+				if err != nil {
+					res.err = fmt.Errorf("erro ao criar arquivo temporário para a saída de '%s': %w", files[i], err)
+					close(res.done)
+					continue
+				}
+				res.out = tmp
+				out := bufio.NewWriterSize(tmp, 64*1024)
+				res.matches, res.err = processFile(files[i], matcher, out, &res.info, true)
+				if err := out.Flush(); err != nil && res.err == nil {
+					res.err = fmt.Errorf("erro ao gravar a saída de '%s': %w", files[i], err)
+				}
+				close(res.done)
+			}
+		}()
+	}
+	if !*confirmWrite {
+		go func() {
+			for i := range files {
+				jobs <- i
+			}
+			close(jobs)
+		}()
+	}
+
+	totalMatches, filesWithMatches, failed := 0, 0, 0
+	for i, res := range results {
+		<-res.done
+		if res.out != nil {
+			if _, err := res.out.Seek(0, io.SeekStart); err == nil {
+				io.Copy(os.Stdout, res.out)
+			}
+			res.out.Close()
+			os.Remove(res.out.Name())
+		}
+		info.Write(res.info.Bytes())
+		if res.err != nil {
+			log.Printf("Erro: %v\n", res.err)
+			failed++
+			continue
+		}
+		totalMatches += res.matches
+		if res.matches > 0 {
+			filesWithMatches++
+		}
+		// Contagem por arquivo: no stdout com -c (como o grep), senão como mensagem informativa
+		if *countOnly {
+			fmt.Printf("%s:%d\n", files[i], res.matches)
+		} else {
+			fmt.Fprintf(info, "%s: %d linha(s) selecionada(s)\n", files[i], res.matches)
+		}
+	}
+	fmt.Fprintf(info, "Total: %d linha(s) selecionada(s) em %d de %d arquivo(s)", totalMatches, filesWithMatches, len(files))
+	if failed > 0 {
+		fmt.Fprintf(info, "; %d arquivo(s) com erro", failed)
+	}
+	fmt.Fprintln(info, ".")
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// expandFileArgs devolve os arquivos a processar; diretórios só são aceitos com -r
+// e são percorridos em ordem lexical, incluindo apenas arquivos regulares.
+// Arquivos inexistentes são mantidos na lista para que o erro apareça por
+// arquivo (nos modos que reescrevem, main recusa a lista antes de começar).
+func expandFileArgs(args []string, recursive bool) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		if !recursive {
+			return nil, fmt.Errorf("'%s' é um diretório (use -r para processá-lo recursivamente)", arg)
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				log.Printf("Aviso: Erro ao acessar '%s', pulando: %v\n", path, err)
+				return nil
+			}
+			// Ignora os temporários de reescritas em andamento
+			if d.Type().IsRegular() && !strings.Contains(d.Name(), ".pop-") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// processFile seleciona as linhas de um arquivo, exibe-as em out e, com -R/-to,
+// reescreve o arquivo sem elas. Devolve a quantidade de linhas selecionadas.
+// Com vários arquivos (multi), o nome do arquivo aparece no banner ou, com
+// -plain, antes de cada linha (como o grep -H).
+func processFile(filePath string, matcher lineMatcher, out *bufio.Writer, info io.Writer, multi bool) (int, error) {
+	// -to é um "pop" de verdade: o destino não pode ser o próprio arquivo
+	if *popTarget != "" && samePath(*popTarget, filePath) {
+		return 0, fmt.Errorf("o arquivo de destino de -to não pode ser o próprio arquivo processado ('%s')", filePath)
+	}
+
+	// Um link simbólico é seguido: o lock, o temporário e o rename valem para o
+	// arquivo apontado (no diretório dele), e o link continua sendo um link
+	realPath := filePath
+	if resolved, err := filepath.EvalSymlinks(filePath); err == nil {
+		realPath = resolved
+	}
+
+	// Qualquer reescrita do arquivo acontece com o lock exclusivo mantido desde a
+	// leitura até o rename; a leitura sem -R usa um lock compartilhado
+	lockedFile, err := openLocked(realPath, *removeMatches)
+	if err != nil {
+		// Verifica se o erro é "arquivo não encontrado" para uma mensagem mais específica
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("arquivo não encontrado '%s'", filePath)
+		}
+		return 0, fmt.Errorf("erro ao abrir arquivo '%s': %w", filePath, err)
+	}
+	defer lockedFile.Close()
+
+	printer := &linePrinter{
+		w:            out,
+		matcher:      matcher,
+		before:       *beforeContext,
+		after:        *afterContext,
+		lineNumbers:  *lineNumbers,
+		onlyMatching: *onlyMatching,
+		banners:      !*plainOutput && !*countOnly && !*diffMode,
+		silent:       *countOnly || *diffMode,
+	}
+	if multi && *plainOutput {
+		printer.prefix = filePath
+	} else if multi {
+		printer.title = filePath
+	}
+
+	// isCandidate diz se a linha corresponde à seleção (regex, invertida com -v)
+	isCandidate := func(line string) bool {
+		if matcher == nil {
+			return !*invertMatch
+		}
+		return matcher.MatchString(line) != *invertMatch
+	}
+
+	// Com -tail, as candidatas a partir de skipCandidates são as N últimas.
+	// Isso exige uma primeira passada só para contar (ainda sob o lock).
+	skipCandidates := 0
+	if *tailCount > 0 {
+		total := 0
+		err := forEachLine(lockedFile, func(raw string) error {
+			if isCandidate(strings.TrimSuffix(raw, "\n")) {
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("erro ao ler arquivo '%s': %w", filePath, err)
+		}
+		if total > *tailCount {
+			skipCandidates = total - *tailCount
+		}
+		if _, err := lockedFile.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("erro ao ler arquivo '%s': %w", filePath, err)
+		}
+	}
+
+	// O arquivo é processado em streaming: com -R as linhas mantidas vão direto
+	// para um temporário no mesmo diretório (depois renomeado sobre o original)
+	// e, com -to, as selecionadas vão para outro temporário até serem gravadas
+	// no destino. A memória usada não depende do tamanho do arquivo.
+	var kept, popped *tempOutput
+	if *removeMatches {
+		if kept, err = newTempOutput(realPath); err != nil {
+			return 0, fmt.Errorf("erro ao criar arquivo temporário para '%s': %w", filePath, err)
+		}
+		defer kept.discard()
+		if *popTarget != "" {
+			if popped, err = newTempOutput(realPath); err != nil {
+				return 0, fmt.Errorf("erro ao criar arquivo temporário para '%s': %w", filePath, err)
+			}
+			defer popped.discard()
+		}
+	}
+
+	// Com -diff, as linhas selecionadas aparecem como removidas (o que -R faria)
+	var diff *textdiff.Writer
+	if *diffMode {
+		diff = textdiff.NewWriter(out, filePath, *diffContext)
+	}
+
+	candidates := 0
+	matchCount := 0
+	lineNum := 0
+
+	// Itera pelas linhas, separando as selecionadas (match, ou não-match com -v) das demais.
+	// raw inclui o '\n' final (ausente apenas na última linha de um arquivo sem newline final).
+	err = forEachLine(lockedFile, func(raw string) error {
+		lineNum++
+		line := strings.TrimSuffix(raw, "\n")
+		selected := isCandidate(line)
+		if selected {
+			candidates++
+			switch {
+			case *headCount > 0:
+				selected = candidates <= *headCount
+			case *tailCount > 0:
+				selected = candidates > skipCandidates
+			}
+		}
+		printer.line(lineNum, line, selected)
+		if diff != nil {
+			if selected {
+				diff.Change(line, nil)
+			} else {
+				diff.Keep(line)
+			}
+		}
+		if selected {
+			matchCount++
+			// Com -R a linha selecionada não volta para o arquivo; com -to ela vai para o destino
+			if popped != nil {
+				popped.w.WriteString(line)
+				popped.w.WriteByte('\n')
+			}
+		} else if kept != nil {
+			kept.w.WriteString(raw)
+		}
+		return nil
+	})
+	if err != nil {
+		return matchCount, fmt.Errorf("erro ao ler arquivo '%s': %w", filePath, err)
+	}
+	printer.finish()
+	if diff != nil {
+		diff.Finish()
+	}
+
+	// Exibe o resultado (as linhas já foram exibidas pelo printer)
+	if matchCount == 0 && !*plainOutput && !*countOnly && !*diffMode && !multi {
+		fmt.Fprintln(out, "Nenhuma linha correspondeu à expressão regular.")
+	}
+	// As mensagens informativas podem ir para o mesmo stdout: esvazia o buffer antes
+	if err := out.Flush(); err != nil {
+		return matchCount, err
+	}
+
+	// Com -confirm o arquivo continua sob o lock enquanto o usuário decide
+	if *removeMatches && *confirmWrite && matchCount > 0 {
+		if !prompt.Confirm(fmt.Sprintf("Remover %d linha(s) de '%s'?", matchCount, filePath)) {
+			fmt.Fprintf(info, "Nenhuma alteração foi feita em '%s'.\n", filePath)
+			return matchCount, nil
+		}
+	}
+
+	// Se -R foi setado, substitui o arquivo pela versão SEM as linhas selecionadas
+	if *removeMatches {
+		if matchCount > 0 { // Só reescreve se houve correspondências para remover
+			fmt.Fprintf(info, "Removendo %d linha(s) correspondente(s) do arquivo '%s'...\n", matchCount, filePath)
+
+			// Usa permissões do arquivo original se possível, senão default 0644
+			fileInfo, statErr := lockedFile.Stat()
+			perms := os.FileMode(0644) // Default
+			if statErr == nil {
+				perms = fileInfo.Mode().Perm() // Pega permissões existentes
+			} else {
+				fmt.Fprintf(info, "Aviso: Não foi possível ler as permissões originais de '%s', usando 0644: %v\n", filePath, statErr)
+			}
+			if err := kept.finish(perms); err != nil {
+				return matchCount, fmt.Errorf("erro ao escrever alterações do arquivo '%s': %w", filePath, err)
+			}
+			if err := checkReplace(realPath, lockedFile); err != nil {
+				return matchCount, fmt.Errorf("erro ao escrever alterações no arquivo '%s': %w", filePath, err)
+			}
+			// O dono só é mantido se houver permissão; uma fila compartilhada por
+			// vários usuários continua funcionando e passa a ser do usuário atual
+			if err := preserveOwner(kept.name, lockedFile); err != nil {
+				fmt.Fprintf(info, "Aviso: Não foi possível manter o dono de '%s', o arquivo passa a ser do usuário atual: %v\n", filePath, err)
+			}
+
+			// Com -to, o destino é gravado (e sincronizado) ANTES de o original ser
+			// substituído e depois de tudo o que pode falhar na preparação: se algo
+			// falhar antes, nenhuma linha se perde e o arquivo original continua
+			// intacto. Cada linha termina em exatamente um dos dois arquivos quando
+			// a operação conclui.
+			if popped != nil {
+				if err := popped.finish(0600); err != nil {
+					return matchCount, fmt.Errorf("erro ao preparar as linhas de '%s' para o destino: %w", filePath, err)
+				}
+				if err := writeTarget(*popTarget, popped.name); err != nil {
+					return matchCount, fmt.Errorf("erro ao gravar as linhas de '%s' no arquivo de destino '%s': %w", filePath, *popTarget, err)
+				}
+				fmt.Fprintf(info, "%d linha(s) gravada(s) em '%s'.\n", matchCount, *popTarget)
+			}
+
+			// Substitui o original de forma atômica (rename do temporário)
+			if err := replaceLocked(kept.name, realPath, lockedFile); err != nil {
+				return matchCount, fmt.Errorf("erro ao escrever alterações no arquivo '%s': %w", filePath, err)
+			}
+			fmt.Fprintf(info, "Arquivo '%s' atualizado com sucesso.\n", filePath)
+		} else if !multi {
+			fmt.Fprintln(info, "Nenhuma linha para remover, o arquivo permanece inalterado.")
+		}
+	}
+	return matchCount, nil
+}
+
+// forEachLine lê o arquivo linha a linha, sem limite de tamanho de linha, e
+// chama fn com cada linha incluindo o '\n' final (quando existir).
+func forEachLine(r io.Reader, fn func(raw string) error) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		raw, err := reader.ReadString('\n')
+		if len(raw) > 0 {
+			if fnErr := fn(raw); fnErr != nil {
+				return fnErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// tempOutput é um arquivo temporário no mesmo diretório do arquivo processado,
+// para que o rename final seja atômico (mesmo sistema de arquivos).
+type tempOutput struct {
+	file *os.File
+	name string
+	w    *bufio.Writer
+}
+
+func newTempOutput(forPath string) (*tempOutput, error) {
+	file, err := os.CreateTemp(filepath.Dir(forPath), "."+filepath.Base(forPath)+".pop-*")
+	if err != nil {
+		return nil, err
+	}
+	return &tempOutput{file: file, name: file.Name(), w: bufio.NewWriterSize(file, 64*1024)}, nil
+}
+
+// finish grava o buffer, sincroniza com o disco e fecha o temporário
+func (t *tempOutput) finish(perms os.FileMode) error {
+	if err := t.w.Flush(); err != nil {
+		return err
+	}
+	if err := t.file.Sync(); err != nil {
+		return err
+	}
+	if err := t.file.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(t.name, perms)
+}
+
+// discard remove o temporário; sem efeito depois do rename
+func (t *tempOutput) discard() {
+	t.file.Close()
+	os.Remove(t.name)
+}
+
+// targetMu serializa as gravações no destino de -to entre os workers
+var (
+	targetMu        sync.Mutex
+	targetTruncated bool // com -truncate, o destino já foi esvaziado por este processo
+)
+
+// writeTarget acrescenta o conteúdo de srcPath (as linhas retiradas, uma por
+// linha) ao final do destino de -to, sob flock (outros processos podem estar
+// gravando no mesmo destino) e sincronizando com o disco. Com -truncate, a
+// primeira gravação esvazia o destino (já sob o lock): se nada for retirado,
+// ou se o usuário recusar no -confirm, o destino não é tocado.
+func writeTarget(path, srcPath string) error {
+	targetMu.Lock()
+	defer targetMu.Unlock()
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := lockFile(file, true); err != nil {
+		return err
+	}
+	if *truncTarget && !targetTruncated {
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		targetTruncated = true
+	}
+
+	// Se o destino não termina com newline, a primeira linha movida não pode grudar na última existente
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			if _, err := file.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := io.Copy(file, src); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// openLocked abre o arquivo com flock (exclusivo ou compartilhado). Como a
+// reescrita troca o arquivo por rename, o lock pode ter sido obtido em um
+// inode que já foi substituído; nesse caso o arquivo é reaberto até que o
+// descritor travado corresponda ao caminho atual.
+func openLocked(path string, exclusive bool) (*os.File, error) {
+	for {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := lockFile(file, exclusive); err != nil {
+			file.Close()
+			return nil, err
+		}
+		lockedInfo, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		currentInfo, err := os.Stat(path)
+		if err == nil && os.SameFile(lockedInfo, currentInfo) {
+			return file, nil // O lock é liberado ao fechar o arquivo
+		}
+		file.Close()
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+}
+
+// samePath informa se dois caminhos apontam para o mesmo arquivo
+func samePath(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+	absA, _ := filepath.Abs(a)
+	absB, _ := filepath.Abs(b)
+	return absA == absB
+}
+
+// linePrinter exibe as linhas selecionadas no estilo do grep, recebendo as
+// linhas uma a uma: guarda até `before` linhas anteriores e conta as
+// `after` linhas seguintes para exibir o contexto.
+type linePrinter struct {
+	w            *bufio.Writer
+	matcher      lineMatcher
+	before       int
+	after        int
+	lineNumbers  bool
+	onlyMatching bool
+	banners      bool
+	silent       bool   // -c: nada é exibido além da contagem
+	prefix       string // nome do arquivo antes de cada linha (-plain com vários arquivos)
+	title        string // nome do arquivo no banner (vários arquivos)
+
+	pending   []numberedLine // contexto anterior ainda não exibido
+	afterLeft int            // linhas de contexto posterior que faltam exibir
+	lastShown int            // número da última linha exibida (0 = nenhuma)
+}
+
+type numberedLine struct {
+	num  int
+	text string
+}
+
+// line recebe a próxima linha do arquivo e diz se ela foi selecionada
+func (p *linePrinter) line(num int, text string, selected bool) {
+	if p.silent {
+		return
+	}
+	if selected {
+		for _, ctx := range p.pending {
+			p.show(ctx.num, ctx.text, '-')
+		}
+		p.pending = p.pending[:0]
+		if p.onlyMatching {
+			for _, part := range p.matcher.FindAllString(text, -1) {
+				p.show(num, part, ':')
+			}
+		} else {
+			p.show(num, text, ':')
+		}
+		p.afterLeft = p.after
+		return
+	}
+	if p.onlyMatching {
+		return
+	}
+	if p.afterLeft > 0 {
+		p.afterLeft--
+		p.show(num, text, '-')
+		return
+	}
+	if p.before > 0 {
+		if len(p.pending) == p.before {
+			p.pending = append(p.pending[:0], p.pending[1:]...)
+		}
+		p.pending = append(p.pending, numberedLine{num: num, text: text})
+	}
+}
+
+// show exibe uma linha; sep é ':' para linhas selecionadas e '-' para contexto
+func (p *linePrinter) show(num int, text string, sep byte) {
+	if p.lastShown == 0 && p.banners {
+		if p.title != "" {
+			fmt.Fprintf(p.w, "--- Linhas Correspondentes: %s ---\n", p.title)
+		} else {
+			fmt.Fprintln(p.w, "--- Linhas Correspondentes ---")
+		}
+	}
+	// Separa grupos de contexto não contíguos, como o grep
+	if (p.before > 0 || p.after > 0) && p.lastShown > 0 && num > p.lastShown+1 {
+		fmt.Fprintln(p.w, "--")
+	}
+	if p.prefix != "" {
+		fmt.Fprintf(p.w, "%s%c", p.prefix, sep)
+	}
+	if p.lineNumbers {
+		fmt.Fprintf(p.w, "%d%c", num, sep)
+	}
+	fmt.Fprintln(p.w, text)
+	p.lastShown = num
+}
+
+// finish fecha o banner se alguma linha foi exibida
+func (p *linePrinter) finish() {
+	if p.lastShown > 0 && p.banners {
+		fmt.Fprintln(p.w, "----------------------------")
+	}
+}
+
+// -------------------- Padrões --------------------
+
+// lineMatcher é implementado por *regexp.Regexp e pelo matcher Aho-Corasick (-F)
+type lineMatcher interface {
+	MatchString(s string) bool
+	FindAllString(s string, n int) []string
+}
+
+// readPatternFile lê um padrão por linha, ignorando linhas vazias
+func readPatternFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// newLineMatcher monta o matcher para todos os padrões. Regexes são unidas em
+// uma única alternância; com -F usa Aho-Corasick (ou um conjunto, com -x).
+func newLineMatcher(patterns []string, fixed, whole bool) (lineMatcher, error) {
+	if fixed {
+		if whole {
+			set := make(lineSet, len(patterns))
+			for _, p := range patterns {
+				set[p] = struct{}{}
+			}
+			return set, nil
+		}
+		return newAhoCorasick(patterns), nil
+	}
+
+	if len(patterns) == 0 {
+		// Nenhum padrão (ex.: -f com arquivo vazio): nenhuma linha corresponde
+		return regexp.MustCompile(`[^\x00-\x{10FFFF}]`), nil
+	}
+	parts := make([]string, len(patterns))
+	for i, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("'%s': %w", p, err)
+		}
+		parts[i] = "(?:" + p + ")"
+	}
+	expr := strings.Join(parts, "|")
+	if whole {
+		expr = "^(?:" + expr + ")$"
+	}
+	return regexp.Compile(expr)
+}
+
+// lineSet casa linhas idênticas a algum padrão (-F -x)
+type lineSet map[string]struct{}
+
+func (ls lineSet) MatchString(s string) bool {
+	_, ok := ls[s]
+	return ok
+}
+
+func (ls lineSet) FindAllString(s string, n int) []string {
+	if n == 0 || !ls.MatchString(s) {
+		return nil
+	}
+	return []string{s}
+}
+
+// ahoCorasick procura todos os padrões literais em uma única passada pela
+// linha, com custo proporcional ao tamanho da linha e não ao número de padrões.
+type ahoCorasick struct {
+	nodes []acNode
+	root  [256]int32 // transições completas da raiz (o estado mais visitado)
+}
+
+type acNode struct {
+	edges    []acEdge // ordenadas por byte
+	fail     int32    // nó do maior sufixo próprio que também é prefixo de algum padrão
+	dict     int32    // próximo nó na cadeia de falhas que encerra um padrão (-1 = nenhum)
+	length   int32    // tamanho do padrão que termina neste nó (0 = nenhum)
+	terminal bool     // algum padrão termina aqui ou na cadeia de falhas
+}
+
+type acEdge struct {
+	b  byte
+	to int32
+}
+
+func (n *acNode) next(b byte) int32 {
+	// Busca binária manual: a maioria dos nós tem poucas arestas e este é o laço mais quente
+	lo, hi := 0, len(n.edges)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if n.edges[mid].b < b {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(n.edges) && n.edges[lo].b == b {
+		return n.edges[lo].to
+	}
+	return -1
+}
+
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	ac := &ahoCorasick{nodes: []acNode{{dict: -1}}}
+
+	// Trie com todos os padrões
+	for _, p := range patterns {
+		cur := int32(0)
+		for i := 0; i < len(p); i++ {
+			next := ac.nodes[cur].next(p[i])
+			if next < 0 {
+				next = int32(len(ac.nodes))
+				ac.nodes = append(ac.nodes, acNode{dict: -1})
+				edges := ac.nodes[cur].edges
+				j := sort.Search(len(edges), func(j int) bool { return edges[j].b >= p[i] })
+				edges = append(edges, acEdge{})
+				copy(edges[j+1:], edges[j:])
+				edges[j] = acEdge{b: p[i], to: next}
+				ac.nodes[cur].edges = edges
+			}
+			cur = next
+		}
+		ac.nodes[cur].length = int32(len(p))
+		ac.nodes[cur].terminal = true
+	}
+
+	// Links de falha em largura (BFS): o pai sempre é resolvido antes do filho
+	queue := []int32{0}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, e := range ac.nodes[u].edges {
+			v := e.to
+			fail := int32(0)
+			if u != 0 {
+				f := ac.nodes[u].fail
+				for {
+					if next := ac.nodes[f].next(e.b); next >= 0 {
+						fail = next
+						break
+					}
+					if f == 0 {
+						break
+					}
+					f = ac.nodes[f].fail
+				}
+			}
+			ac.nodes[v].fail = fail
+			if ac.nodes[fail].length > 0 {
+				ac.nodes[v].dict = fail
+			} else {
+				ac.nodes[v].dict = ac.nodes[fail].dict
+			}
+			ac.nodes[v].terminal = ac.nodes[v].terminal || ac.nodes[fail].terminal
+			queue = append(queue, v)
+		}
+	}
+
+	for b := 0; b < 256; b++ {
+		ac.root[b] = 0
+		if next := ac.nodes[0].next(byte(b)); next >= 0 {
+			ac.root[b] = next
+		}
+	}
+	return ac
+}
+
+// step avança o autômato com o próximo byte
+func (ac *ahoCorasick) step(state int32, b byte) int32 {
+	for state != 0 {
+		if next := ac.nodes[state].next(b); next >= 0 {
+			return next
+		}
+		state = ac.nodes[state].fail
+	}
+	return ac.root[b]
+}
+
+func (ac *ahoCorasick) MatchString(s string) bool {
+	if ac.nodes[0].terminal {
+		return true // padrão vazio casa com tudo
+	}
+	state := int32(0)
+	for i := 0; i < len(s); i++ {
+		state = ac.step(state, s[i])
+		if ac.nodes[state].terminal {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAllString devolve as ocorrências sem sobreposição, escolhendo sempre a
+// que começa mais à esquerda e, entre essas, a mais longa (como o grep -o).
+func (ac *ahoCorasick) FindAllString(s string, n int) []string {
+	// longest[start] = maior padrão que começa em start
+	longest := make(map[int]int)
+	state := int32(0)
+	for i := 0; i < len(s); i++ {
+		state = ac.step(state, s[i])
+		node := state
+		if ac.nodes[node].length == 0 {
+			node = ac.nodes[node].dict
+		}
+		for ; node > 0; node = ac.nodes[node].dict {
+			length := int(ac.nodes[node].length)
+			start := i + 1 - length
+			if length > longest[start] {
+				longest[start] = length
+			}
+		}
+	}
+
+	var found []string
+	for start := 0; start < len(s) && (n < 0 || len(found) < n); {
+		if length, ok := longest[start]; ok {
+			found = append(found, s[start:start+length])
+			start += length
+			continue
+		}
+		start++
+	}
+	return found
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		line     string
+		match    bool
+		found    []string
+	}{
+		{"nenhum padrão", nil, "abc", false, nil},
+		{"padrão vazio casa com tudo", []string{""}, "abc", true, nil},
+		{"linha vazia", []string{"a"}, "", false, nil},
+		{"um padrão", []string{"lo"}, "hello", true, []string{"lo"}},
+		{"sem ocorrência", []string{"xyz", "abd"}, "abcabc", false, nil},
+		{"várias ocorrências", []string{"ab"}, "abxab", true, []string{"ab", "ab"}},
+		{"padrão dentro de outro", []string{"he", "she", "his", "hers"}, "ushers", true, []string{"she"}},
+		{"mais longo no mesmo início", []string{"a", "ab", "abc"}, "abcab", true, []string{"abc", "ab"}},
+		{"mais à esquerda vence", []string{"bc", "abc"}, "abcd", true, []string{"abc"}},
+		{"sem sobreposição", []string{"aa"}, "aaaaa", true, []string{"aa", "aa"}},
+		{"falha depois de prefixo parcial", []string{"abcd", "bc"}, "abcx", true, []string{"bc"}},
+		{"sufixo pela cadeia de falhas", []string{"abcde", "cd"}, "xabcdx", true, []string{"cd"}},
+		{"bytes não ASCII", []string{"ção", "ã"}, "ação", true, []string{"ção"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := newAhoCorasick(tt.patterns)
+			if got := ac.MatchString(tt.line); got != tt.match {
+				t.Errorf("MatchString(%q) = %v, quer %v", tt.line, got, tt.match)
+			}
+			if got := ac.FindAllString(tt.line, -1); !reflect.DeepEqual(got, tt.found) {
+				t.Errorf("FindAllString(%q, -1) = %q, quer %q", tt.line, got, tt.found)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllLimit(t *testing.T) {
+	ac := newAhoCorasick([]string{"a", "b"})
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, nil},
+		{1, []string{"a"}},
+		{2, []string{"a", "b"}},
+		{-1, []string{"a", "b", "a"}},
+	}
+	for _, tt := range tests {
+		if got := ac.FindAllString("xaxbxa", tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindAllString(%q, %d) = %q, quer %q", "xaxbxa", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNewLineMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		fixed    bool
+		whole    bool
+		match    []string
+		noMatch  []string
+	}{
+		{
+			name:     "regexes unidas",
+			patterns: []string{`^a\d`, `z$`},
+			match:    []string{"a1", "xyz", "a9z"},
+			noMatch:  []string{"ab", "za", ""},
+		},
+		{
+			name:     "regex com alternância própria",
+			patterns: []string{`a|b`, `c`},
+			match:    []string{"xa", "b", "c"},
+			noMatch:  []string{"d"},
+		},
+		{
+			name:     "-x com regexes",
+			patterns: []string{`a|b`, `c+`},
+			whole:    true,
+			match:    []string{"a", "b", "ccc"},
+			noMatch:  []string{"ab", "xc", ""},
+		},
+		{
+			name:     "-F trata o padrão como literal",
+			patterns: []string{"a.b", "[x]"},
+			fixed:    true,
+			match:    []string{"-a.b-", "[x]"},
+			noMatch:  []string{"axb", "x"},
+		},
+		{
+			name:     "-F -x compara a linha inteira",
+			patterns: []string{"a.b", "c"},
+			fixed:    true,
+			whole:    true,
+			match:    []string{"a.b", "c"},
+			noMatch:  []string{"a.bc", "cc", "axb"},
+		},
+		{
+			name:    "sem padrões nada casa",
+			noMatch: []string{"", "a", "\x00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newLineMatcher(tt.patterns, tt.fixed, tt.whole)
+			if err != nil {
+				t.Fatalf("newLineMatcher(%q): %v", tt.patterns, err)
+			}
+			for _, line := range tt.match {
+				if !m.MatchString(line) {
+					t.Errorf("%q deveria casar", line)
+				}
+			}
+			for _, line := range tt.noMatch {
+				if m.MatchString(line) {
+					t.Errorf("%q não deveria casar", line)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLineMatcherInvalidRegex(t *testing.T) {
+	_, err := newLineMatcher([]string{"ok", "a(b"}, false, false)
+	if err == nil || !strings.Contains(err.Error(), "'a(b'") {
+		t.Errorf("newLineMatcher: erro %v, quer um que cite 'a(b'", err)
+	}
+}
+
+// setFlags aplica as flags de uma chamada de processFile e restaura os valores
+// originais no fim do teste
+func setFlags(t *testing.T, set func()) {
+	t.Helper()
+	remove, invert, plain := *removeMatches, *invertMatch, *plainOutput
+	target, truncate := *popTarget, *truncTarget
+	head, tail := *headCount, *tailCount
+	t.Cleanup(func() {
+		*removeMatches, *invertMatch, *plainOutput = remove, invert, plain
+		*popTarget, *truncTarget = target, truncate
+		*headCount, *tailCount = head, tail
+		targetTruncated = false
+	})
+	*plainOutput = true
+	targetTruncated = false
+	set()
+}
+
+// runProcessFile processa path com a regex pattern ("" = sem padrão, como no
+// modo fila) e devolve as linhas exibidas
+func runProcessFile(t *testing.T, path, pattern string) (string, int, error) {
+	t.Helper()
+	var matcher lineMatcher
+	if pattern != "" {
+		matcher = regexp.MustCompile(pattern)
+	}
+	var out strings.Builder
+	w := bufio.NewWriter(&out)
+	n, err := processFile(path, matcher, w, io.Discard, false)
+	w.Flush()
+	return out.String(), n, err
+}
+
+// writeFile cria o arquivo name em dir com o conteúdo dado e devolve o caminho
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// Com -to cada linha termina em exatamente um dos dois arquivos, inclusive
+// quando a gravação no destino falha
+func TestProcessFilePopTo(t *testing.T) {
+	const src = "a1\nb\na2\n"
+	tests := []struct {
+		name       string
+		pattern    string
+		invert     bool
+		truncate   bool
+		target     *string // conteúdo inicial do destino (nil = não existe)
+		targetDir  bool    // o destino é um diretório: a gravação falha
+		wantSrc    string
+		wantTarget string
+		wantCount  int
+		wantErr    bool
+	}{
+		{name: "destino novo", pattern: "a", wantSrc: "b\n", wantTarget: "a1\na2\n", wantCount: 2},
+		{name: "acrescenta ao destino", pattern: "a", target: ptr("x\n"), wantSrc: "b\n", wantTarget: "x\na1\na2\n", wantCount: 2},
+		{name: "destino sem newline final", pattern: "a", target: ptr("x"), wantSrc: "b\n", wantTarget: "x\na1\na2\n", wantCount: 2},
+		{name: "-truncate esvazia o destino", pattern: "a", truncate: true, target: ptr("x\n"), wantSrc: "b\n", wantTarget: "a1\na2\n", wantCount: 2},
+		{name: "-v move as que não casam", pattern: "a", invert: true, target: ptr(""), wantSrc: "a1\na2\n", wantTarget: "b\n", wantCount: 1},
+		{name: "nada casa: nada muda", pattern: "z", truncate: true, target: ptr("x\n"), wantSrc: src, wantTarget: "x\n"},
+		{name: "falha no destino mantém o original", pattern: "a", targetDir: true, wantSrc: src, wantCount: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeFile(t, dir, "fila.txt", src)
+			target := filepath.Join(dir, "feitos.txt")
+			if tt.target != nil {
+				writeFile(t, dir, "feitos.txt", *tt.target)
+			}
+			if tt.targetDir {
+				if err := os.Mkdir(target, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			setFlags(t, func() {
+				*removeMatches = true
+				*popTarget = target
+				*truncTarget = tt.truncate
+				*invertMatch = tt.invert
+			})
+
+			_, n, err := runProcessFile(t, path, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("processFile: erro %v, quer erro = %v", err, tt.wantErr)
+			}
+			if n != tt.wantCount {
+				t.Errorf("processFile = %d, quer %d", n, tt.wantCount)
+			}
+			if got := readFile(t, path); got != tt.wantSrc {
+				t.Errorf("arquivo = %q, quer %q", got, tt.wantSrc)
+			}
+			if !tt.targetDir {
+				if got := readFile(t, target); got != tt.wantTarget {
+					t.Errorf("destino = %q, quer %q", got, tt.wantTarget)
+				}
+			}
+			// Nenhum temporário (.fila.txt.pop-*) pode sobrar no diretório
+			if left, _ := filepath.Glob(filepath.Join(dir, ".*.pop-*")); len(left) > 0 {
+				t.Errorf("temporários restantes: %q", left)
+			}
+		})
+	}
+}
+
+func TestProcessFilePopToRejectsSameFile(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "fila.txt", "a\n")
+	setFlags(t, func() {
+		*removeMatches = true
+		*popTarget = path
+	})
+	if _, _, err := runProcessFile(t, path, "a"); err == nil {
+		t.Fatal("processFile: erro esperado com -to apontando para o próprio arquivo")
+	}
+	if got := readFile(t, path); got != "a\n" {
+		t.Errorf("arquivo = %q, quer %q", got, "a\n")
+	}
+}
+
+// Um link simbólico continua sendo link e o arquivo apontado é reescrito
+func TestProcessFileFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	real := writeFile(t, dir, "real.txt", "a\nb\n")
+	link := filepath.Join(dir, "link.txt")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("links simbólicos indisponíveis: %v", err)
+	}
+	setFlags(t, func() { *removeMatches = true })
+	if _, _, err := runProcessFile(t, link, "a"); err != nil {
+		t.Fatalf("processFile: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("'%s' deixou de ser um link simbólico", link)
+	}
+	if got := readFile(t, real); got != "b\n" {
+		t.Errorf("arquivo apontado = %q, quer %q", got, "b\n")
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+// -head/-tail retiram do arquivo as primeiras/últimas N linhas candidatas e as exibem
+func TestProcessFileQueue(t *testing.T) {
+	const src = "a1\nb1\na2\nb2\na3\n"
+	tests := []struct {
+		name       string
+		head, tail int
+		pattern    string
+		toTarget   bool
+		wantOut    string
+		wantSrc    string
+		wantTarget string
+	}{
+		{name: "-head", head: 2, wantOut: "a1\nb1\n", wantSrc: "a2\nb2\na3\n"},
+		{name: "-tail", tail: 2, wantOut: "b2\na3\n", wantSrc: "a1\nb1\na2\n"},
+		{name: "-head maior que o arquivo", head: 10, wantOut: src, wantSrc: ""},
+		{name: "-tail maior que o arquivo", tail: 10, wantOut: src, wantSrc: ""},
+		{name: "-head com padrão", head: 1, pattern: "^b", wantOut: "b1\n", wantSrc: "a1\na2\nb2\na3\n"},
+		{name: "-tail com padrão", tail: 2, pattern: "^a", wantOut: "a2\na3\n", wantSrc: "a1\nb1\nb2\n"},
+		{name: "-head com padrão sem candidatas", head: 1, pattern: "^z", wantSrc: src},
+		{name: "-head com -to", head: 2, toTarget: true, wantOut: "a1\nb1\n", wantSrc: "a2\nb2\na3\n", wantTarget: "a1\nb1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeFile(t, dir, "jobs.txt", src)
+			target := filepath.Join(dir, "feitos.txt")
+			setFlags(t, func() {
+				*removeMatches = true
+				*headCount = tt.head
+				*tailCount = tt.tail
+				if tt.toTarget {
+					*popTarget = target
+				}
+			})
+
+			out, n, err := runProcessFile(t, path, tt.pattern)
+			if err != nil {
+				t.Fatalf("processFile: %v", err)
+			}
+			if out != tt.wantOut {
+				t.Errorf("exibido = %q, quer %q", out, tt.wantOut)
+			}
+			if want := strings.Count(tt.wantOut, "\n"); n != want {
+				t.Errorf("processFile = %d, quer %d", n, want)
+			}
+			if got := readFile(t, path); got != tt.wantSrc {
+				t.Errorf("arquivo = %q, quer %q", got, tt.wantSrc)
+			}
+			if tt.toTarget {
+				if got := readFile(t, target); got != tt.wantTarget {
+					t.Errorf("destino = %q, quer %q", got, tt.wantTarget)
+				}
+			}
+		})
+	}
+}
+
+// A reescrita em streaming mantém as linhas restantes byte a byte (fins de
+// linha, última linha sem newline, linhas maiores que o buffer) e as permissões
+func TestProcessFileStreamedRewrite(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	tests := []struct {
+		name    string
+		src     string
+		pattern string
+		want    string
+	}{
+		{name: "remove do meio", src: "a\nb\nc\n", pattern: "b", want: "a\nc\n"},
+		{name: "última linha sem newline é mantida", src: "a\nb\nc", pattern: "b", want: "a\nc"},
+		{name: "remove a última linha sem newline", src: "a\nb", pattern: "b", want: "a\n"},
+		{name: "CRLF é mantido", src: "a\r\nb\r\nc\r\n", pattern: "b", want: "a\r\nc\r\n"},
+		{name: "linha maior que o buffer", src: long + "\nb\n" + long, pattern: "^b$", want: long + "\n" + long},
+		{name: "remove tudo", src: "b\nb\n", pattern: "b", want: ""},
+		{name: "nada casa", src: "a\n", pattern: "b", want: "a\n"},
+		{name: "arquivo vazio", src: "", pattern: "b", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeFile(t, dir, "dados.txt", tt.src)
+			if err := os.Chmod(path, 0640); err != nil {
+				t.Fatal(err)
+			}
+			setFlags(t, func() { *removeMatches = true })
+
+			if _, _, err := runProcessFile(t, path, tt.pattern); err != nil {
+				t.Fatalf("processFile: %v", err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("arquivo = %.60q (%d bytes), quer %.60q (%d bytes)", got, len(got), tt.want, len(tt.want))
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm() != 0640 {
+				t.Errorf("permissões = %v, quer %v", info.Mode().Perm(), os.FileMode(0640))
+			}
+			if left, _ := filepath.Glob(filepath.Join(dir, ".*.pop-*")); len(left) > 0 {
+				t.Errorf("temporários restantes: %q", left)
+			}
+		})
+	}
+}
@@ -1,12 +1,22 @@
+// rename_files renomeia arquivos removendo/adicionando prefixos e sufixos ou
+// aplicando scripts de edição ao nome.
 package main
 
 import (
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+
+	"fsgo/internal/cliflag"
+	"fsgo/internal/script"
+	"fsgo/internal/version"
+	"fsgo/internal/walk"
 )
 
 // Definindo as flags no escopo do pacote para serem acessíveis na função Usage
@@ -17,8 +27,16 @@ var (
 	addpos  = flag.String("addpos", "", "String a adicionar no final do nome de arquivo")
 	inplace = flag.Bool("I", false, "Renomear in-place (sobrescreve o arquivo antigo)")
 	dirMode = flag.String("dir", "", "Se especificado, percorre todo este `diretório` para renomear arquivos")
+	jobs    = flag.Int("j", runtime.NumCPU(), "Número de diretórios lidos em paralelo com -dir")
+	scripts cliflag.StringList
+
+	showVersion = flag.Bool("version", false, "Exibe a versão e sai")
 )
 
+func init() {
+	flag.Var(&scripts, "e", "Script de edição do nome (mesma linguagem do edit_lines; repetível; aplicado depois das flags acima)")
+}
+
 func main() {
 	// Define a função de Usage personalizada ANTES de flag.Parse()
 	flag.Usage = func() {
@@ -28,7 +46,7 @@ func main() {
 		// Nome do executável
 		progName := filepath.Base(os.Args[0])
 
-		fmt.Fprintf(output, "%s: Renomeia arquivos removendo/adicionando prefixos/sufixos.\n\n", progName)
+		fmt.Fprintf(output, "%s %s: Renomeia arquivos removendo/adicionando prefixos/sufixos.\n\n", progName, version.Version)
 		fmt.Fprintf(output, "Uso:\n")
 		fmt.Fprintf(output, "  1. %s [opções] <arquivo1> [arquivo2...]\n", progName)
 		fmt.Fprintf(output, "  2. %s -dir <diretório> [opções]\n\n", progName)
@@ -41,10 +59,17 @@ func main() {
 		fmt.Fprintf(output, "     (mostra: arquivo1.txt -> arquivo1.txt.bkp)\n")
 		fmt.Fprintf(output, "  %s -I -rmpre 'draft-' -dir ./documentos\n", progName)
 		fmt.Fprintf(output, "     (renomeia todos os arquivos em ./documentos que começam com 'draft-', removendo o prefixo)\n")
+		fmt.Fprintf(output, "  %s -e \"lower | replace ' ' '_' | if /^img/ then addpre 'foto_'\" -dir ./fotos\n", progName)
+		fmt.Fprintf(output, "     (o script é aplicado apenas ao nome base; veja as operações em edit_lines -h)\n")
 	}
 
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), version.String())
+		return
+	}
+
 	// Verifica se a combinação de argumentos é válida
 	// Precisa de um diretório (-dir) OU de pelo menos um arquivo como argumento
 	if *dirMode == "" && flag.NArg() == 0 {
@@ -53,6 +78,30 @@ func main() {
 		os.Exit(1)   // Sai com código de erro
 	}
 
+	// As quatro flags são operações embutidas, aplicadas antes dos scripts -e
+	var flagScript []string
+	if *rmpre != "" {
+		flagScript = append(flagScript, "trimpre "+script.Quote(*rmpre))
+	}
+	if *rmpos != "" {
+		flagScript = append(flagScript, "trimpos "+script.Quote(*rmpos))
+	}
+	if *addpre != "" {
+		flagScript = append(flagScript, "addpre "+script.Quote(*addpre))
+	}
+	if *addpos != "" {
+		flagScript = append(flagScript, "addpos "+script.Quote(*addpos))
+	}
+	var ops []script.Op
+	for _, src := range append([]string{strings.Join(flagScript, " | ")}, scripts...) {
+		op, err := script.Parse(src)
+		if err != nil {
+			log.Fatalf("Erro no script '%s': %v\n", src, err)
+		}
+		ops = append(ops, op)
+	}
+	transform := script.Chain(ops...)
+
 	// Se a flag -dir foi fornecida, percorre o diretório
 	if *dirMode != "" {
 		info, err := os.Stat(*dirMode)
@@ -62,21 +111,28 @@ func main() {
 		}
 
 		fmt.Printf("Percorrendo diretório: %s\n", *dirMode)
-		err = filepath.Walk(*dirMode, func(path string, f os.FileInfo, err error) error {
+		// Primeiro coleta todos os arquivos, depois renomeia em ordem:
+		// renomear durante a busca poderia fazer um arquivo ser visto duas vezes
+		var files []string
+		err = walk.Parallel(*dirMode, *jobs, false, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				log.Printf("Aviso: Erro ao acessar '%s', pulando: %v\n", path, err)
 				return nil // Continua a percorrer outros arquivos/subdiretórios
 			}
-			// Processa apenas se for um arquivo e não o próprio diretório raiz percorrido
-			if !f.IsDir() && path != *dirMode {
-				renameFile(path, *rmpre, *rmpos, *addpre, *addpos, *inplace)
+			// Processa apenas arquivos
+			if !d.IsDir() {
+				files = append(files, path)
 			}
 			return nil
 		})
 		if err != nil {
-			// Erro durante o Walk (raro se os erros individuais forem tratados)
+			// Erro durante a busca (raro se os erros individuais forem tratados)
 			log.Fatalf("Erro fatal ao percorrer o diretório '%s': %v", *dirMode, err)
 		}
+		sort.Strings(files)
+		for _, path := range files {
+			renameFile(path, transform, *inplace)
+		}
 		fmt.Println("Processamento do diretório concluído.")
 		return // Termina a execução após processar o diretório
 	}
@@ -89,28 +145,22 @@ func main() {
 			log.Printf("Erro: Arquivo '%s' não encontrado.\n", oldPath)
 			continue // Pula para o próximo arquivo
 		}
-		renameFile(oldPath, *rmpre, *rmpos, *addpre, *addpos, *inplace)
+		renameFile(oldPath, transform, *inplace)
 	}
 	fmt.Println("Processamento de arquivos individuais concluído.")
 }
 
-func renameFile(oldPath, rmpre, rmpos, addpre, addpos string, inplace bool) {
+func renameFile(oldPath string, transform script.Op, inplace bool) {
 	dir := filepath.Dir(oldPath)
 	base := filepath.Base(oldPath)
 
 	// Aplica as transformações apenas na parte do nome (base)
-	newName := base
-	if rmpre != "" {
-		newName = strings.TrimPrefix(newName, rmpre)
-	}
-	if rmpos != "" {
-		newName = strings.TrimSuffix(newName, rmpos)
-	}
-	if addpre != "" {
-		newName = addpre + newName
-	}
-	if addpos != "" {
-		newName = newName + addpos
+	newName := transform(base)
+
+	// Um script não pode produzir um nome vazio ou mover o arquivo para outro diretório
+	if newName == "" || strings.ContainsRune(newName, filepath.Separator) {
+		log.Printf("Erro: Nome inválido gerado para '%s': '%s'\n", oldPath, newName)
+		return
 	}
 
 	// Se o nome não mudou, não faz nada
@@ -132,4 +182,4 @@ func renameFile(oldPath, rmpre, rmpos, addpre, addpos string, inplace bool) {
 		// Apenas mostra o que seria feito
 		fmt.Printf("Simulação: %s -> %s\n", oldPath, newPath)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,3 @@
+module fsgo
+
+go 1.22
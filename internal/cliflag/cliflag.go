@@ -0,0 +1,17 @@
+// Package cliflag reúne os tipos de flag compartilhados pelas ferramentas.
+package cliflag
+
+import "strings"
+
+// StringList acumula os valores de uma flag que pode ser repetida
+// (flag.Var(&lista, "e", "...") e depois -e a -e b)
+type StringList []string
+
+func (s *StringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *StringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,33 @@
+package cliflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestStringList(t *testing.T) {
+	tests := []struct {
+		args []string
+		want StringList
+		str  string
+	}{
+		{nil, nil, ""},
+		{[]string{"-e", "a"}, StringList{"a"}, "a"},
+		{[]string{"-e", "a", "-e", "", "-e", "b,c"}, StringList{"a", "", "b,c"}, "a,,b,c"},
+	}
+	for _, tt := range tests {
+		var list StringList
+		fs := flag.NewFlagSet("t", flag.ContinueOnError)
+		fs.Var(&list, "e", "padrão")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%q): %v", tt.args, err)
+		}
+		if !reflect.DeepEqual(list, tt.want) {
+			t.Errorf("Parse(%q) = %q, quer %q", tt.args, list, tt.want)
+		}
+		if got := list.String(); got != tt.str {
+			t.Errorf("String() = %q, quer %q", got, tt.str)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package prompt faz perguntas interativas no terminal.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// input é compartilhado entre as perguntas para não perder respostas já lidas
+var input = bufio.NewReader(os.Stdin)
+
+// Confirm mostra a pergunta no stderr e lê a resposta do stdin;
+// apenas "s", "sim", "y" ou "yes" confirmam (fim da entrada = não)
+func Confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [s/N] ", question)
+	answer, _ := input.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "s", "sim", "y", "yes":
+		return true
+	}
+	return false
+}
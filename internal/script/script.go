@@ -0,0 +1,298 @@
+// Package script implementa a linguagem de edição de linhas usada pelo -e de
+// edit_lines e rename_files.
+//
+// Um script é uma sequência de operações separadas por '|', aplicadas da
+// esquerda para a direita:
+//
+//	trimpre 'old_' | upper | replace '/' '\\' | addpos '.new'
+//	if /^#/ then trimpre '#' | trim else addpre '# ' end | lower
+//
+// Argumentos podem ser palavras simples ou strings entre aspas simples ou
+// duplas (com escapes \\, \', \", \n e \t). Regexes são escritas entre
+// barras (/.../, com \/ para uma barra literal). O 'end' de um if pode ser
+// omitido quando ele vai até o fim do script.
+package script
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Op transforma uma linha (ou um nome de arquivo)
+type Op func(string) string
+
+// builtins lista as operações disponíveis: número de argumentos e construtor
+var builtins = map[string]struct {
+	nargs int
+	build func(args []token) (Op, error)
+}{
+	"trimpre": {1, func(a []token) (Op, error) {
+		s := a[0].text
+		return func(line string) string { return strings.TrimPrefix(line, s) }, nil
+	}},
+	"trimpos": {1, func(a []token) (Op, error) {
+		s := a[0].text
+		return func(line string) string { return strings.TrimSuffix(line, s) }, nil
+	}},
+	"addpre": {1, func(a []token) (Op, error) {
+		s := a[0].text
+		return func(line string) string { return s + line }, nil
+	}},
+	"addpos": {1, func(a []token) (Op, error) {
+		s := a[0].text
+		return func(line string) string { return line + s }, nil
+	}},
+	"replace": {2, func(a []token) (Op, error) {
+		old, repl := a[0].text, a[1].text
+		return func(line string) string { return strings.ReplaceAll(line, old, repl) }, nil
+	}},
+	"sub": {2, func(a []token) (Op, error) {
+		if a[0].kind != tokRegex {
+			return nil, fmt.Errorf("posição %d: 'sub' espera uma /regex/ como primeiro argumento", a[0].pos)
+		}
+		re, err := regexp.Compile(a[0].text)
+		if err != nil {
+			return nil, fmt.Errorf("posição %d: regex inválida: %w", a[0].pos, err)
+		}
+		repl := a[1].text
+		return func(line string) string { return re.ReplaceAllString(line, repl) }, nil
+	}},
+	"upper": {0, func([]token) (Op, error) { return strings.ToUpper, nil }},
+	"lower": {0, func([]token) (Op, error) { return strings.ToLower, nil }},
+	"trim":  {0, func([]token) (Op, error) { return strings.TrimSpace, nil }},
+}
+
+// Sinônimos com os nomes das flags antigas
+func init() {
+	builtins["rmpre"] = builtins["trimpre"]
+	builtins["rmpos"] = builtins["trimpos"]
+}
+
+const (
+	tokWord   = 'w' // palavra simples (nome de operação ou argumento)
+	tokString = 's' // string entre aspas
+	tokRegex  = 'r' // /regex/
+	tokPipe   = '|'
+)
+
+type token struct {
+	kind byte
+	text string
+	pos  int // posição (1-based) no script, para mensagens de erro
+}
+
+// Chain compõe as operações na ordem dada
+func Chain(ops ...Op) Op {
+	var active []Op
+	for _, op := range ops {
+		if op != nil {
+			active = append(active, op)
+		}
+	}
+	return func(line string) string {
+		for _, op := range active {
+			line = op(line)
+		}
+		return line
+	}
+}
+
+// Parse compila um script em uma única operação
+func Parse(src string) (Op, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	op, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.toks) {
+		return nil, fmt.Errorf("posição %d: '%s' inesperado", p.toks[p.i].pos, p.toks[p.i].text)
+	}
+	return op, nil
+}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '|':
+			toks = append(toks, token{kind: tokPipe, text: "|", pos: i + 1})
+			i++
+		case c == '\'' || c == '"' || c == '/':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(src) {
+				ch := src[i]
+				if ch == c {
+					closed = true
+					i++
+					break
+				}
+				if ch == '\\' && i+1 < len(src) {
+					next := src[i+1]
+					switch {
+					case c == '/' && next == '/':
+						sb.WriteByte('/')
+					case c == '/':
+						// Dentro de regex os escapes são repassados como estão
+						sb.WriteByte('\\')
+						sb.WriteByte(next)
+					case next == 'n':
+						sb.WriteByte('\n')
+					case next == 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(next)
+					}
+					i += 2
+					continue
+				}
+				sb.WriteByte(ch)
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("posição %d: %c sem fechamento", start+1, c)
+			}
+			kind := byte(tokString)
+			if c == '/' {
+				kind = tokRegex
+			}
+			toks = append(toks, token{kind: kind, text: sb.String(), pos: start + 1})
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(" \t\n\r|'\"", rune(src[i])) {
+				i++
+			}
+			toks = append(toks, token{kind: tokWord, text: src[start:i], pos: start + 1})
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peekWord(words ...string) bool {
+	if p.i >= len(p.toks) || p.toks[p.i].kind != tokWord {
+		return false
+	}
+	for _, w := range words {
+		if p.toks[p.i].text == w {
+			return true
+		}
+	}
+	return false
+}
+
+// pipeline lê estágios separados por '|' até o fim do script ou um 'else'/'end'
+func (p *parser) pipeline() (Op, error) {
+	var ops []Op
+	for p.i < len(p.toks) && !p.peekWord("else", "end") {
+		op, err := p.stage()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+		if p.i < len(p.toks) && p.toks[p.i].kind == tokPipe {
+			p.i++
+			if p.i >= len(p.toks) || p.peekWord("else", "end") {
+				return nil, fmt.Errorf("posição %d: operação esperada depois de '|'", p.toks[p.i-1].pos)
+			}
+			continue
+		}
+		if p.i < len(p.toks) && !p.peekWord("else", "end") {
+			return nil, fmt.Errorf("posição %d: '|' esperado antes de '%s'", p.toks[p.i].pos, p.toks[p.i].text)
+		}
+	}
+	return Chain(ops...), nil
+}
+
+// stage lê uma operação com seus argumentos, ou um if/then/else/end
+func (p *parser) stage() (Op, error) {
+	tok := p.toks[p.i]
+	if tok.kind != tokWord {
+		return nil, fmt.Errorf("posição %d: nome de operação esperado, encontrado '%s'", tok.pos, tok.text)
+	}
+	p.i++
+
+	if tok.text == "if" {
+		return p.conditional(tok)
+	}
+
+	builtin, ok := builtins[tok.text]
+	if !ok {
+		return nil, fmt.Errorf("posição %d: operação desconhecida '%s'", tok.pos, tok.text)
+	}
+	var args []token
+	for len(args) < builtin.nargs {
+		if p.i >= len(p.toks) || p.toks[p.i].kind == tokPipe {
+			return nil, fmt.Errorf("posição %d: '%s' espera %d argumento(s)", tok.pos, tok.text, builtin.nargs)
+		}
+		args = append(args, p.toks[p.i])
+		p.i++
+	}
+	return builtin.build(args)
+}
+
+// conditional lê: if [not] /regex/ then <pipeline> [else <pipeline>] [end]
+func (p *parser) conditional(ifTok token) (Op, error) {
+	negate := false
+	if p.peekWord("not") {
+		negate = true
+		p.i++
+	}
+	if p.i >= len(p.toks) || p.toks[p.i].kind != tokRegex {
+		return nil, fmt.Errorf("posição %d: 'if' espera uma /regex/", ifTok.pos)
+	}
+	re, err := regexp.Compile(p.toks[p.i].text)
+	if err != nil {
+		return nil, fmt.Errorf("posição %d: regex inválida: %w", p.toks[p.i].pos, err)
+	}
+	p.i++
+	if !p.peekWord("then") {
+		return nil, fmt.Errorf("posição %d: 'then' esperado depois da regex do 'if'", ifTok.pos)
+	}
+	p.i++
+
+	thenOp, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	elseOp := Op(nil)
+	if p.peekWord("else") {
+		p.i++
+		if elseOp, err = p.pipeline(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peekWord("end") {
+		p.i++
+	}
+
+	return func(line string) string {
+		if re.MatchString(line) != negate {
+			return thenOp(line)
+		}
+		if elseOp != nil {
+			return elseOp(line)
+		}
+		return line
+	}, nil
+}
+
+// Quote escreve s como string do script, com os escapes necessários
+func Quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
@@ -0,0 +1,102 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		in   string
+		want string
+	}{
+		{"vazio", "", "abc", "abc"},
+		{"trimpre", "trimpre old_", "old_file", "file"},
+		{"trimpre sem o prefixo", "trimpre old_", "file", "file"},
+		{"rmpre é sinônimo", "rmpre 'x'", "xab", "ab"},
+		{"trimpos", "trimpos .bak", "a.txt.bak", "a.txt"},
+		{"rmpos é sinônimo", "rmpos .bak", "a.bak", "a"},
+		{"addpre e addpos", "addpre '# ' | addpos ';'", "x", "# x;"},
+		{"replace", `replace '/' '\\'`, "a/b/c", `a\b\c`},
+		{"sub com grupo", "sub /(\\d+)-(\\d+)/ '$2-$1'", "10-20", "20-10"},
+		{"sub com barra escapada", `sub /a\/b/ 'x'`, "a/b", "x"},
+		{"upper", "upper", "abc", "ABC"},
+		{"lower", "lower", "ABC", "abc"},
+		{"trim", "trim", "  a b  ", "a b"},
+		{"ordem da esquerda para a direita", "addpos X | lower", "a", "ax"},
+		{"aspas duplas com escapes", `addpos "\t\"\n"`, "a", "a\t\"\n"},
+		{"if então", "if /^#/ then trimpre '#' | trim end", "# x", "x"},
+		{"if sem corresponder", "if /^#/ then upper end", "abc", "abc"},
+		{"if else", "if /^#/ then trimpre '#' else addpre '# ' end | lower", "ABC", "# abc"},
+		{"if not", "if not /\\.go$/ then addpos .txt end", "a", "a.txt"},
+		{"if sem end no fim", "if /a/ then upper", "a", "A"},
+		{"if aninhado", "if /a/ then if /b/ then upper end end", "ab", "AB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			if got := op(tt.in); got != tt.want {
+				t.Errorf("Parse(%q)(%q) = %q, quer %q", tt.src, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"nope", "operação desconhecida 'nope'"},
+		{"trimpre", "'trimpre' espera 1 argumento(s)"},
+		{"replace a", "'replace' espera 2 argumento(s)"},
+		{"upper |", "operação esperada depois de '|'"},
+		{"upper lower", "'|' esperado antes de 'lower'"},
+		{"addpre 'abc", "' sem fechamento"},
+		{"sub 'a' 'b'", "'sub' espera uma /regex/"},
+		{"sub /(/ 'b'", "regex inválida"},
+		{"if 'a' then upper", "'if' espera uma /regex/"},
+		{"if /a/ upper", "'then' esperado"},
+		{"upper end", "'end' inesperado"},
+		{"'a'", "nome de operação esperado"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil {
+				t.Fatalf("Parse(%q): erro esperado", tt.src)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q): erro %q, quer algo com %q", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	op := Chain(strings.ToUpper, nil, func(s string) string { return s + "!" })
+	if got := op("a"); got != "A!" {
+		t.Errorf("Chain(...)(%q) = %q, quer %q", "a", got, "A!")
+	}
+	if got := Chain()("a"); got != "a" {
+		t.Errorf("Chain()(%q) = %q, quer %q", "a", got, "a")
+	}
+}
+
+// Quote precisa produzir um argumento que o Parse lê de volta igual
+func TestQuoteRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "abc", "it's", `a\b`, "tab\tnl\n", `"aspas"`, "a | b"} {
+		op, err := Parse("addpre " + Quote(s))
+		if err != nil {
+			t.Fatalf("Parse(addpre %s): %v", Quote(s), err)
+		}
+		if got := op(""); got != s {
+			t.Errorf("Quote(%q) lido de volta como %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package textdiff gera diffs unificados (formato aceito pelo patch) das
+// alterações feitas linha a linha por edit_lines e pop_lines.
+package textdiff
+
+import (
+	"fmt"
+	"io"
+)
+
+// Writer gera um diff unificado em streaming. edit_lines e pop_lines só
+// trocam ou removem linhas no lugar (sem reordenar), então o alinhamento entre
+// o original e o resultado é direto e não é preciso calcular uma LCS: cada
+// linha original é mantida (Keep) ou trocada por zero ou mais linhas (Change).
+// Apenas o hunk atual fica em memória.
+type Writer struct {
+	w       io.Writer
+	path    string
+	context int
+
+	oldNum, newNum int      // linhas já consumidas do original e do resultado
+	before         []string // até context linhas inalteradas antes do próximo hunk
+	pending        []string // linhas inalteradas desde a última alteração do hunk
+	hunk           []string // linhas do hunk atual, já com o prefixo ' ', '-' ou '+'
+	oldStart       int
+	newStart       int
+	inHunk         bool
+	changed        bool // algum hunk foi gerado
+}
+
+// NewWriter escreve em w o diff de path com context linhas de contexto
+func NewWriter(w io.Writer, path string, context int) *Writer {
+	if context < 0 {
+		context = 0
+	}
+	return &Writer{w: w, path: path, context: context}
+}
+
+// Keep registra uma linha que não mudou
+func (d *Writer) Keep(line string) {
+	d.oldNum++
+	d.newNum++
+	if !d.inHunk {
+		d.before = append(d.before, line)
+		if len(d.before) > d.context {
+			d.before = d.before[1:]
+		}
+		return
+	}
+	d.pending = append(d.pending, line)
+	// Mais de 2*context linhas inalteradas: o próximo hunk não encosta neste
+	if len(d.pending) > 2*d.context {
+		d.closeHunk()
+	}
+}
+
+// Change registra que a linha old foi trocada pelas linhas new (nenhuma = removida)
+func (d *Writer) Change(old string, new []string) {
+	if !d.inHunk {
+		d.inHunk = true
+		d.oldStart = d.oldNum + 1 - len(d.before)
+		d.newStart = d.newNum + 1 - len(d.before)
+		d.addContext(d.before)
+		d.before = nil
+	} else {
+		d.addContext(d.pending)
+		d.pending = nil
+	}
+	d.hunk = append(d.hunk, "-"+old)
+	for _, line := range new {
+		d.hunk = append(d.hunk, "+"+line)
+	}
+	d.oldNum++
+	d.newNum += len(new)
+}
+
+// Finish escreve o último hunk; devolve se houve alguma diferença
+func (d *Writer) Finish() bool {
+	if d.inHunk {
+		d.closeHunk()
+	}
+	return d.changed
+}
+
+func (d *Writer) addContext(lines []string) {
+	for _, line := range lines {
+		d.hunk = append(d.hunk, " "+line)
+	}
+}
+
+// closeHunk escreve o hunk com até context linhas de contexto depois da última
+// alteração; as linhas pendentes restantes viram o contexto anterior do próximo
+func (d *Writer) closeHunk() {
+	n := len(d.pending)
+	if n > d.context {
+		n = d.context
+	}
+	d.addContext(d.pending[:n])
+	rest := d.pending[n:]
+	if len(rest) > d.context {
+		rest = rest[len(rest)-d.context:]
+	}
+	d.before = append([]string(nil), rest...)
+	d.pending = nil
+
+	if !d.changed {
+		fmt.Fprintf(d.w, "--- %s\t(original)\n+++ %s\t(modificado)\n", d.path, d.path)
+		d.changed = true
+	}
+	oldCount, newCount := 0, 0
+	for _, line := range d.hunk {
+		switch line[0] {
+		case ' ':
+			oldCount++
+			newCount++
+		case '-':
+			oldCount++
+		case '+':
+			newCount++
+		}
+	}
+	fmt.Fprintf(d.w, "@@ -%s +%s @@\n", hunkRange(d.oldStart, oldCount), hunkRange(d.newStart, newCount))
+	for _, line := range d.hunk {
+		fmt.Fprintln(d.w, line)
+	}
+	d.hunk = nil
+	d.inHunk = false
+}
+
+// hunkRange formata "início,quantidade"; um intervalo vazio aponta para a linha anterior
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,97 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+// step é uma linha do original: mantida ou trocada por new (vazio = removida)
+type step struct {
+	keep bool
+	old  string
+	new  []string
+}
+
+func keep(line string) step                 { return step{keep: true, old: line} }
+func change(old string, new ...string) step { return step{old: old, new: new} }
+
+const header = "--- f.txt\t(original)\n+++ f.txt\t(modificado)\n"
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		context int
+		steps   []step
+		want    string
+	}{
+		{
+			name:    "sem alterações",
+			context: 3,
+			steps:   []step{keep("a"), keep("b")},
+			want:    "",
+		},
+		{
+			name:    "remoção no meio",
+			context: 1,
+			steps:   []step{keep("a"), keep("b"), change("c"), keep("d"), keep("e")},
+			want:    header + "@@ -2,3 +2,2 @@\n b\n-c\n d\n",
+		},
+		{
+			name:    "troca na primeira linha",
+			context: 3,
+			steps:   []step{change("a", "A"), keep("b"), keep("c")},
+			want:    header + "@@ -1,3 +1,3 @@\n-a\n+A\n b\n c\n",
+		},
+		{
+			name:    "uma linha vira duas",
+			context: 0,
+			steps:   []step{keep("a"), change("b", "b1", "b2"), keep("c")},
+			want:    header + "@@ -2,1 +2,2 @@\n-b\n+b1\n+b2\n",
+		},
+		{
+			name:    "alterações próximas no mesmo hunk",
+			context: 1,
+			steps:   []step{keep("1"), change("2"), keep("3"), change("4"), keep("5")},
+			want:    header + "@@ -1,5 +1,3 @@\n 1\n-2\n 3\n-4\n 5\n",
+		},
+		{
+			name:    "alterações distantes em hunks separados",
+			context: 1,
+			steps: []step{keep("1"), change("2", "X"), keep("3"), keep("4"), keep("5"),
+				keep("6"), keep("7"), change("8"), keep("9")},
+			want: header + "@@ -1,3 +1,3 @@\n 1\n-2\n+X\n 3\n" + "@@ -7,3 +7,2 @@\n 7\n-8\n 9\n",
+		},
+		{
+			name:    "arquivo inteiro removido",
+			context: 3,
+			steps:   []step{change("a")},
+			want:    header + "@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "contexto negativo vale zero",
+			context: -1,
+			steps:   []step{keep("a"), change("b", "B"), keep("c")},
+			want:    header + "@@ -2,1 +2,1 @@\n-b\n+B\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			d := NewWriter(&out, "f.txt", tt.context)
+			for _, s := range tt.steps {
+				if s.keep {
+					d.Keep(s.old)
+				} else {
+					d.Change(s.old, s.new)
+				}
+			}
+			changed := d.Finish()
+			if got := out.String(); got != tt.want {
+				t.Errorf("diff:\n%s\nquer:\n%s", got, tt.want)
+			}
+			if changed != (tt.want != "") {
+				t.Errorf("Finish() = %v, quer %v", changed, tt.want != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Package version guarda a versão das ferramentas. Os valores são injetados
+// pelo fsgo -buildAll com -ldflags "-X fsgo/internal/version.Version=...";
+// em um go build comum ficam os padrões abaixo.
+package version
+
+var (
+	Version = "dev" // git describe --tags --always --dirty
+	Commit  = ""    // hash curto do commit
+	Date    = ""    // data da compilação (RFC 3339, UTC)
+)
+
+// String devolve a versão com o commit e a data, quando conhecidos,
+// ex.: "v1.2.0 (commit 3f2a9c1, compilado em 2025-04-24T10:00:00Z)"
+func String() string {
+	s := Version
+	switch {
+	case Commit != "" && Date != "":
+		s += " (commit " + Commit + ", compilado em " + Date + ")"
+	case Commit != "":
+		s += " (commit " + Commit + ")"
+	case Date != "":
+		s += " (compilado em " + Date + ")"
+	}
+	return s
+}
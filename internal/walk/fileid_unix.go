@@ -0,0 +1,16 @@
+//go:build !windows
+
+package walk
+
+import (
+	"os"
+	"syscall"
+)
+
+func getFileID(info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
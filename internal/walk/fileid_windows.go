@@ -0,0 +1,9 @@
+package walk
+
+import "os"
+
+// No Windows o os.FileInfo não traz um identificador de arquivo (seria preciso
+// abrir cada diretório), então não há detecção de loops de links com -L
+func getFileID(info os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,144 @@
+// Package walk percorre árvores de diretórios lendo vários diretórios em
+// paralelo (usado por list_files e rename_files).
+package walk
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// fileID identifica um diretório pelo par dispositivo/inode (detecção de loops);
+// getFileID depende do sistema (fileid_*.go)
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// Parallel percorre root lendo até `workers` diretórios simultaneamente
+// com os.ReadDir (sem Lstat por entrada). visit é chamada de forma serial,
+// em ordem não determinística, para cada entrada abaixo de root; quem chama
+// deve ordenar o resultado. Se visit devolver filepath.SkipDir para um
+// diretório, ele não é percorrido; qualquer outro erro interrompe a busca.
+// Falhas ao ler um diretório são repassadas como visit(dir, nil, err).
+//
+// Com followLinks, links simbólicos são resolvidos (a entrada passa a ter o
+// tipo do alvo) e links para diretórios são percorridos; um link que aponta
+// para um diretório ancestral é reportado como visit(path, d, err).
+func Parallel(root string, workers int, followLinks bool, visit func(path string, d fs.DirEntry, err error) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type dirJob struct {
+		dir       string
+		ancestors []fileID // apenas com followLinks
+	}
+	type dirResult struct {
+		job     dirJob
+		entries []fs.DirEntry
+		err     error
+	}
+
+	rootJob := dirJob{dir: root}
+	if followLinks {
+		if info, err := os.Stat(root); err == nil {
+			if id, ok := getFileID(info); ok {
+				rootJob.ancestors = []fileID{id}
+			}
+		}
+	}
+
+	jobs := make(chan dirJob)
+	results := make(chan dirResult)
+	done := make(chan struct{})
+	defer close(done)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range jobs {
+				entries, err := os.ReadDir(job.dir)
+				select {
+				case results <- dirResult{job: job, entries: entries, err: err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	defer close(jobs)
+
+	// Diretórios ainda não enviados aos workers e quantos estão sendo lidos
+	pending := []dirJob{rootJob}
+	inFlight := 0
+	for len(pending) > 0 || inFlight > 0 {
+		var send chan dirJob
+		var next dirJob
+		if len(pending) > 0 {
+			send = jobs
+			next = pending[len(pending)-1]
+		}
+
+		select {
+		case send <- next:
+			pending = pending[:len(pending)-1]
+			inFlight++
+		case res := <-results:
+			inFlight--
+			if res.err != nil {
+				if err := visit(res.job.dir, nil, res.err); err != nil && err != filepath.SkipDir {
+					return err
+				}
+			}
+			// os.ReadDir devolve as entradas lidas antes de um eventual erro
+			for _, d := range res.entries {
+				path := filepath.Join(res.job.dir, d.Name())
+				isLink := d.Type()&fs.ModeSymlink != 0
+				if followLinks && isLink {
+					// Links quebrados continuam sendo reportados como links
+					if info, err := os.Stat(path); err == nil {
+						d = fs.FileInfoToDirEntry(info)
+					}
+				}
+
+				var ancestors []fileID
+				if followLinks && d.IsDir() {
+					if info, err := d.Info(); err == nil {
+						if id, ok := getFileID(info); ok {
+							if isLink && containsFileID(res.job.ancestors, id) {
+								loopErr := fmt.Errorf("loop de links simbólicos: '%s' aponta para um diretório ancestral", path)
+								if err := visit(path, d, loopErr); err != nil && err != filepath.SkipDir {
+									return err
+								}
+								continue
+							}
+							ancestors = append(res.job.ancestors[:len(res.job.ancestors):len(res.job.ancestors)], id)
+						}
+					}
+				}
+
+				err := visit(path, d, nil)
+				if err == filepath.SkipDir {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					pending = append(pending, dirJob{dir: path, ancestors: ancestors})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsFileID(ids []fileID, id fileID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package walk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// makeTree cria os diretórios (terminados em /) e arquivos de paths em um
+// diretório temporário e, para cada par de links, um link simbólico
+func makeTree(t *testing.T, paths []string, links map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if strings.HasSuffix(p, "/") {
+			if err := os.MkdirAll(full, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(p), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Skipf("links simbólicos indisponíveis: %v", err)
+		}
+	}
+	return root
+}
+
+// collect percorre root e devolve os caminhos relativos visitados (com "/" nos
+// diretórios e " !" nas entradas reportadas com erro), em ordem
+func collect(t *testing.T, root string, workers int, followLinks bool, skip string) []string {
+	t.Helper()
+	var got []string
+	err := Parallel(root, workers, followLinks, func(path string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			t.Fatal(relErr)
+		}
+		rel = filepath.ToSlash(rel)
+		if d != nil && d.IsDir() {
+			rel += "/"
+		}
+		if err != nil {
+			got = append(got, rel+" !")
+			return nil
+		}
+		got = append(got, rel)
+		if rel == skip {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Parallel: %v", err)
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestParallel(t *testing.T) {
+	tree := []string{"a/x.txt", "a/b/y.txt", "a/b/c/", "c.txt", "vazio/"}
+	tests := []struct {
+		name        string
+		links       map[string]string
+		workers     int
+		followLinks bool
+		skip        string
+		want        []string
+	}{
+		{
+			name:    "um worker",
+			workers: 1,
+			want:    []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt", "vazio/"},
+		},
+		{
+			name:    "vários workers",
+			workers: 8,
+			want:    []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt", "vazio/"},
+		},
+		{
+			name:    "workers inválido vale 1",
+			workers: 0,
+			want:    []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt", "vazio/"},
+		},
+		{
+			name:    "SkipDir não percorre o diretório",
+			workers: 4,
+			skip:    "a/b/",
+			want:    []string{"a/", "a/b/", "a/x.txt", "c.txt", "vazio/"},
+		},
+		{
+			name:    "link para diretório não é seguido sem followLinks",
+			links:   map[string]string{"l": "a/b"},
+			workers: 4,
+			want:    []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt", "l", "vazio/"},
+		},
+		{
+			name:        "link para diretório é seguido com followLinks",
+			links:       map[string]string{"l": "a/b"},
+			workers:     4,
+			followLinks: true,
+			want: []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt",
+				"l/", "l/c/", "l/y.txt", "vazio/"},
+		},
+		{
+			name:        "link quebrado continua sendo link",
+			links:       map[string]string{"quebrado": "nao-existe"},
+			workers:     4,
+			followLinks: true,
+			want:        []string{"a/", "a/b/", "a/b/c/", "a/b/y.txt", "a/x.txt", "c.txt", "quebrado", "vazio/"},
+		},
+		{
+			name:        "link para ancestral é reportado como loop",
+			links:       map[string]string{"a/b/up": ".."},
+			workers:     4,
+			followLinks: true,
+			want:        []string{"a/", "a/b/", "a/b/c/", "a/b/up/ !", "a/b/y.txt", "a/x.txt", "c.txt", "vazio/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.links) > 0 && runtime.GOOS == "windows" {
+				t.Skip("links simbólicos exigem privilégios no Windows")
+			}
+			root := makeTree(t, tree, tt.links)
+			got := collect(t, root, tt.workers, tt.followLinks, tt.skip)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("visitados:\n%s\nquer:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParallelStopsOnError(t *testing.T) {
+	root := makeTree(t, []string{"a/1", "a/2", "b/3"}, nil)
+	stop := errors.New("parar")
+	visited := 0
+	err := Parallel(root, 4, false, func(path string, d fs.DirEntry, err error) error {
+		visited++
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("Parallel devolveu %v, quer %v", err, stop)
+	}
+	if visited != 1 {
+		t.Errorf("visit chamada %d vez(es) depois do erro, quer 1", visited)
+	}
+}
+
+func TestParallelReportsUnreadableRoot(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nao-existe")
+	var gotErr error
+	err := Parallel(missing, 2, false, func(path string, d fs.DirEntry, err error) error {
+		if path == missing && d == nil {
+			gotErr = err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Parallel: %v", err)
+	}
+	if !errors.Is(gotErr, fs.ErrNotExist) {
+		t.Errorf("erro repassado ao visit = %v, quer fs.ErrNotExist", gotErr)
+	}
+}